@@ -2,19 +2,28 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/wojnosystems/go-retry/examples/common"
 	"github.com/wojnosystems/go-retry/retry"
-	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryHTTP"
+	"github.com/wojnosystems/go-retry/retryLoop"
 	"net/http"
 	"time"
 )
 
+// attemptPrinter prints the time since the last attempt as each attempt starts
+type attemptPrinter struct {
+	retryLoop.NopObserver
+	timer *common.TimeSet
+}
+
+func (p attemptPrinter) OnAttemptStart(_ retryLoop.AttemptInfo) {
+	fmt.Println("getting", p.timer.SinceLast())
+}
+
 func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
-	timer := common.NewTimeSet()
 
 	dialerStrategy := &retry.ExponentialMaxWaitUpTo{
 		InitialWaitBetweenAttempts: 50 * time.Millisecond,
@@ -22,25 +31,23 @@ func main() {
 		MaxAttempts:                15,
 		MaxWaitBetweenAttempts:     500 * time.Millisecond,
 	}
+	timer := common.NewTimeSet()
+	dialerStrategy.Observer = attemptPrinter{timer: &timer}
 
-	totalTime := common.TimeThis(func() {
-		err := dialerStrategy.Retry(ctx, func() error {
-			fmt.Println("getting", timer.SinceLast())
-			req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/non-existent", nil)
-			req = req.WithContext(ctx)
-			_, getErr := http.DefaultClient.Do(req)
-			if getErr != nil {
-				getErr = errors.Unwrap(getErr)
-				if getErr != context.DeadlineExceeded {
-					getErr = retryError.Again(getErr)
-				}
-				return getErr
-			}
-			return nil
-		})
+	client := &http.Client{
+		Transport: retryHTTP.NewTransport(http.DefaultTransport, dialerStrategy),
+	}
 
-		// Outputs the http error because we ran out of retries
-		fmt.Println(err)
+	totalTime := common.TimeThis(func() {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:8080/non-existent", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			// Outputs the http error because we ran out of retries
+			fmt.Println(err)
+			return
+		}
+		_ = resp.Body.Close()
+		fmt.Println(resp.Status)
 	})
 	fmt.Println("total time", totalTime)
 }
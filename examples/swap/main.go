@@ -6,14 +6,9 @@ import (
 	"fmt"
 	"github.com/wojnosystems/go-retry/retry"
 	"github.com/wojnosystems/go-retry/retryError"
-	"github.com/wojnosystems/go-retry/retryLoop"
 	"time"
 )
 
-type retryStrategy interface {
-	Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error)
-}
-
 func main() {
 	normal := &retry.LinearUpTo{
 		InitialWaitBetweenAttempts: 100 * time.Millisecond,
@@ -21,7 +16,7 @@ func main() {
 		MaxAttempts:                5,
 	}
 
-	var strategy retryStrategy
+	var strategy retry.Strategy
 	strategy = normal
 
 	_ = strategy.Retry(context.TODO(), func() (err error) {
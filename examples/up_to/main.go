@@ -31,5 +31,5 @@ func main() {
 		})
 	})
 	fmt.Println("tried", tries, "times taking", duration)
-	fmt.Println("should get 'simulated error': ", err.Error())
+	fmt.Println("should get 'retry attempts exhausted: simulated error': ", err.Error())
 }
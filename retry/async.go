@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"context"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"sync"
+	"time"
+)
+
+// Async is a retry running in the background, see RetryAsync. It is safe for concurrent use
+type Async struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	err    error
+
+	mu         sync.Mutex
+	startedAt  time.Time
+	finishedAt time.Time
+	progress   Progress
+}
+
+// RetryAsync starts retrying cb with strategy in its own goroutine and returns right away.
+// Use the returned Async to wait for the result, cancel the retry, or check on its progress.
+// Progress is reported by strategies built on retryLoop.UntilAttempt, which is all of those in this package
+func RetryAsync(ctx context.Context, strategy Strategy, cb retryLoop.AttemptCallbackFunc) *Async {
+	a := &Async{
+		done:      make(chan struct{}),
+		startedAt: time.Now(),
+	}
+	ctx, a.cancel = context.WithCancel(ctx)
+	ctx = retryLoop.WithObserver(ctx, &asyncObserver{async: a})
+	go func() {
+		defer close(a.done)
+		defer a.cancel()
+		a.err = strategy.RetryAttempt(ctx, cb)
+		a.mu.Lock()
+		a.finishedAt = time.Now()
+		a.mu.Unlock()
+	}()
+	return a
+}
+
+// Done is closed once the retry has stopped, whether it succeeded or not
+func (a *Async) Done() <-chan struct{} {
+	return a.done
+}
+
+// Wait blocks until the retry stops and returns its error, which is nil if it succeeded
+func (a *Async) Wait() error {
+	<-a.done
+	return a.err
+}
+
+// Err is the error returned by the retry once Done is closed. Before then, it is nil
+func (a *Async) Err() error {
+	select {
+	case <-a.done:
+		return a.err
+	default:
+		return nil
+	}
+}
+
+// Cancel stops the retry as if its context had been canceled. The attempt in progress is given a canceled context
+// and no more attempts are made. Use Wait to know when it has stopped
+func (a *Async) Cancel() {
+	a.cancel()
+}
+
+// Progress is how far the retry has got. Attempts is the number of attempts started so far and NextWait is the
+// wait in progress, which is 0 while an attempt is running. TotalWait includes the whole of the wait in progress.
+// Elapsed stops growing once the retry stops
+func (a *Async) Progress() Progress {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	progress := a.progress
+	if a.finishedAt.IsZero() {
+		progress.Elapsed = time.Since(a.startedAt)
+	} else {
+		progress.Elapsed = a.finishedAt.Sub(a.startedAt)
+	}
+	return progress
+}
+
+// asyncObserver records the progress of an Async
+type asyncObserver struct {
+	retryLoop.NopObserver
+	async *Async
+}
+
+func (o *asyncObserver) OnAttemptStart(attempt retryLoop.AttemptInfo) {
+	o.async.mu.Lock()
+	defer o.async.mu.Unlock()
+	o.async.progress.Attempts = attempt.Number
+	o.async.progress.NextWait = 0
+}
+
+func (o *asyncObserver) OnWait(_ retryLoop.AttemptInfo, wait time.Duration) {
+	o.async.mu.Lock()
+	defer o.async.mu.Unlock()
+	o.async.progress.NextWait = wait
+	o.async.progress.TotalWait += wait
+}
+
+func (o *asyncObserver) OnGiveUp(_ retryLoop.AttemptInfo, _ retryLoop.GiveUpReason, _ error) {
+	o.async.mu.Lock()
+	defer o.async.mu.Unlock()
+	o.async.progress.NextWait = 0
+}
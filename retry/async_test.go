@@ -0,0 +1,75 @@
+package retry_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("RetryAsync", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		clock  *retryMocks.FakeClock
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+		clock = retryMocks.NewFakeClock(time.Date(2021, 11, 5, 0, 0, 0, 0, time.UTC))
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("returns the result once done", func() {
+		mock := &retryMocks.Callback{Responses: []error{retryError.StopSuccess}}
+		subject := retry.RetryAsync(ctx, retry.NewUpTo(0, 3), mock.AttemptGenerator())
+		Eventually(subject.Done()).Should(BeClosed())
+		Expect(subject.Wait()).Should(Succeed())
+		Expect(subject.Err()).Should(BeNil())
+		Expect(subject.Progress().Attempts).Should(BeNumerically("==", 1))
+	})
+	It("reports progress while waiting", func() {
+		mock := &retryMocks.Callback{Responses: []error{
+			retryMocks.ErrRetry,
+			retryError.StopSuccess,
+		}}
+		strategy := retry.NewUpTo(1*time.Hour, 3)
+		strategy.Clock = clock
+		subject := retry.RetryAsync(ctx, strategy, mock.AttemptGenerator())
+		clock.WaitForSleepers(1)
+		progress := subject.Progress()
+		Expect(progress.Attempts).Should(BeNumerically("==", 1))
+		Expect(progress.NextWait).Should(Equal(1 * time.Hour))
+		Expect(progress.TotalWait).Should(Equal(1 * time.Hour))
+		Consistently(subject.Done()).ShouldNot(BeClosed())
+
+		clock.Advance(1 * time.Hour)
+		Expect(subject.Wait()).Should(Succeed())
+		progress = subject.Progress()
+		Expect(progress.Attempts).Should(BeNumerically("==", 2))
+		Expect(progress.NextWait).Should(BeZero())
+	})
+	It("returns the error when the retry fails", func() {
+		mock := &retryMocks.Callback{Responses: []error{retryMocks.ErrThatCannotBeRetried}}
+		subject := retry.RetryAsync(ctx, retry.NewUpTo(0, 3), mock.AttemptGenerator())
+		Expect(subject.Wait()).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+		Expect(subject.Err()).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+	})
+	When("canceled", func() {
+		It("cancels the attempt in progress and stops", func() {
+			subject := retry.RetryAsync(ctx, retry.NewForever(0), func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			Consistently(subject.Done()).ShouldNot(BeClosed())
+			Expect(subject.Err()).Should(BeNil())
+			subject.Cancel()
+			Expect(subject.Wait()).Should(MatchError(context.Canceled))
+		})
+	})
+})
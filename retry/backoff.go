@@ -0,0 +1,10 @@
+package retry
+
+import "time"
+
+// Backoff calculates how long to wait before each retry. Combine a Backoff with a StopPolicy using a Builder or
+// Composed to make a Strategy
+type Backoff interface {
+	// Delay returns how long to wait after timesWaited previous waits. timesWaited starts at 0
+	Delay(timesWaited uint64) time.Duration
+}
@@ -0,0 +1,26 @@
+package retry
+
+import "time"
+
+// CappedBackoff never waits longer than MaxWaitBetweenAttempts, no matter how long Backoff would wait.
+// A negative wait from Backoff is taken to have overflowed, so it is capped too.
+// When jitter is used, the jitter is applied to the capped wait and the result is capped again
+type CappedBackoff struct {
+	Backoff                Backoff
+	MaxWaitBetweenAttempts time.Duration
+}
+
+func NewCappedBackoff(backoff Backoff, maxWaitBetweenAttempts time.Duration) *CappedBackoff {
+	return &CappedBackoff{
+		Backoff:                backoff,
+		MaxWaitBetweenAttempts: maxWaitBetweenAttempts,
+	}
+}
+
+func (b *CappedBackoff) Delay(timesWaited uint64) time.Duration {
+	delay := b.Backoff.Delay(timesWaited)
+	if delay < 0 {
+		return b.MaxWaitBetweenAttempts
+	}
+	return minDuration(delay, b.MaxWaitBetweenAttempts)
+}
@@ -0,0 +1,18 @@
+package retry
+
+import "time"
+
+// ConstantBackoff waits the same WaitBetweenAttempts before every retry
+type ConstantBackoff struct {
+	WaitBetweenAttempts time.Duration
+}
+
+func NewConstantBackoff(waitBetweenAttempts time.Duration) *ConstantBackoff {
+	return &ConstantBackoff{
+		WaitBetweenAttempts: waitBetweenAttempts,
+	}
+}
+
+func (b *ConstantBackoff) Delay(_ uint64) time.Duration {
+	return b.WaitBetweenAttempts
+}
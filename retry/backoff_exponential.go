@@ -0,0 +1,21 @@
+package retry
+
+import "time"
+
+// ExponentialBackoff grows the wait exponentially by the formula:
+// BackoffTime(i) = InitialWaitBetweenAttempts * (1 + GrowthFactor)^i
+type ExponentialBackoff struct {
+	InitialWaitBetweenAttempts time.Duration
+	GrowthFactor               float64
+}
+
+func NewExponentialBackoff(initialWaitBetweenAttempts time.Duration, growthFactor float64) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		InitialWaitBetweenAttempts: initialWaitBetweenAttempts,
+		GrowthFactor:               growthFactor,
+	}
+}
+
+func (b *ExponentialBackoff) Delay(timesWaited uint64) time.Duration {
+	return exponentialSleepTime(b.InitialWaitBetweenAttempts, b.GrowthFactor, timesWaited)
+}
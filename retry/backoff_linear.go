@@ -0,0 +1,21 @@
+package retry
+
+import "time"
+
+// LinearBackoff grows the wait linearly by the formula:
+// BackoffTime(i) = InitialWaitBetweenAttempts * (1 + GrowthFactor*i)
+type LinearBackoff struct {
+	InitialWaitBetweenAttempts time.Duration
+	GrowthFactor               float64
+}
+
+func NewLinearBackoff(initialWaitBetweenAttempts time.Duration, growthFactor float64) *LinearBackoff {
+	return &LinearBackoff{
+		InitialWaitBetweenAttempts: initialWaitBetweenAttempts,
+		GrowthFactor:               growthFactor,
+	}
+}
+
+func (b *LinearBackoff) Delay(timesWaited uint64) time.Duration {
+	return linearSleepTime(b.InitialWaitBetweenAttempts, b.GrowthFactor, timesWaited)
+}
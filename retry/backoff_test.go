@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"github.com/onsi/gomega"
+	"math"
+	"testing"
+	"time"
+)
+
+// overflowedBackoff is a Backoff whose wait got too long for a Duration
+type overflowedBackoff struct{}
+
+func (overflowedBackoff) Delay(_ uint64) time.Duration {
+	return math.MinInt64
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	cases := map[string]struct {
+		backoff     Backoff
+		timesWaited uint64
+		expected    time.Duration
+	}{
+		"constant": {
+			backoff:     NewConstantBackoff(3 * timeUnit),
+			timesWaited: 10,
+			expected:    3 * timeUnit,
+		},
+		"linear": {
+			backoff:     NewLinearBackoff(1*timeUnit, 2.0),
+			timesWaited: 10,
+			expected:    (1 + 20) * timeUnit,
+		},
+		"exponential": {
+			backoff:     NewExponentialBackoff(1*timeUnit, 1.0),
+			timesWaited: 10,
+			expected:    1_024 * timeUnit,
+		},
+		"capped under": {
+			backoff:     NewCappedBackoff(NewLinearBackoff(1*timeUnit, 1.0), 5*timeUnit),
+			timesWaited: 2,
+			expected:    3 * timeUnit,
+		},
+		"capped over": {
+			backoff:     NewCappedBackoff(NewLinearBackoff(1*timeUnit, 1.0), 5*timeUnit),
+			timesWaited: 10,
+			expected:    5 * timeUnit,
+		},
+		"capped exponential past a Duration": {
+			backoff:     NewCappedBackoff(NewExponentialBackoff(50*timeUnit, 1.0), 1*time.Second),
+			timesWaited: 100,
+			expected:    1 * time.Second,
+		},
+		"capped linear past a Duration": {
+			backoff:     NewCappedBackoff(NewLinearBackoff(1*time.Hour, 1.0), 1*time.Second),
+			timesWaited: math.MaxUint64,
+			expected:    1 * time.Second,
+		},
+		"capped overflowed": {
+			backoff:     NewCappedBackoff(overflowedBackoff{}, 1*time.Second),
+			timesWaited: 10,
+			expected:    1 * time.Second,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			actual := c.backoff.Delay(c.timesWaited)
+			g.Expect(actual).Should(gomega.Equal(c.expected))
+		})
+	}
+}
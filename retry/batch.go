@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+)
+
+// BatchCallbackFunc processes the items that are still pending, such as by writing them all in one request.
+// ctx is the context for the current attempt, see retryLoop.AttemptCallbackFunc.
+// It returns one error per item, in the same order as pending: retryError.StopSuccess (nil) if the item succeeded,
+// an error wrapped with retryError.Again if the item should be retried, or any other error if it failed for good.
+// If the whole call failed, return a nil results and batchErr instead, which applies to every pending item
+type BatchCallbackFunc[T any] func(ctx context.Context, pending []T) (results []error, batchErr error)
+
+// BatchResult is the final outcome of an item
+type BatchResult[T any] struct {
+	// Item is the item given to RetryBatch
+	Item T
+
+	// Err is nil if the item succeeded. Otherwise, it is the item's last error. If the retry stopped while the item
+	// was still to be retried, it is marked the same way as the error returned by the loop, such as with
+	// retryError.ErrAttemptsExhausted
+	Err error
+
+	// Attempts is how many times the item was given to the callback
+	Attempts uint64
+}
+
+// BatchError is returned by RetryBatch when at least one item did not succeed. Check the BatchResult of each item
+// for why
+type BatchError struct {
+	// Failed is how many items did not succeed
+	Failed int
+
+	// Total is how many items were given to RetryBatch
+	Total int
+
+	// Err is the error returned by the strategy if it stopped before every item was done, nil otherwise
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	message := fmt.Sprintf("%d of %d items failed", e.Failed, e.Total)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+// Unwrap returns the error returned by the strategy, if any
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ErrBatchResults is the error given for every item when a BatchCallbackFunc does not return one result per item
+var ErrBatchResults = errors.New("batch callback must return one result per pending item")
+
+// RetryBatch calls callback with items, then retries using strategy, each time calling callback with only the items
+// that should be retried. This saves redoing the items that already succeeded when a bulk operation partially fails.
+// Each attempt retries if any item should be retried and, if several do, waits for the retry after hinted by the last
+// of them, see retryError.AgainAfter.
+// The results are in the same order as items. err is nil if every item succeeded, otherwise it is a *BatchError.
+// strategy must make one attempt at a time, so Hedged cannot be used
+func RetryBatch[T any](ctx context.Context, strategy Strategy, items []T, callback BatchCallbackFunc[T]) (results []BatchResult[T], err error) {
+	results = make([]BatchResult[T], len(items))
+	pending := make([]int, len(items))
+	for i, item := range items {
+		results[i].Item = item
+		pending[i] = i
+	}
+	if len(items) == 0 {
+		return results, nil
+	}
+	attempted := false
+	loopErr := strategy.RetryAttempt(ctx, func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+		attempted = true
+		batch := make([]T, len(pending))
+		for i, index := range pending {
+			batch[i] = items[index]
+			results[index].Attempts++
+		}
+		itemErrs, batchErr := callback(ctx, batch)
+		if batchErr == nil && len(itemErrs) != len(batch) {
+			batchErr = ErrBatchResults
+		}
+		if batchErr != nil {
+			// the loop may still retry errors that aren't marked retryable, such as when the attempt timed out or the
+			// Classifier says so, so the items stay pending until the loop gives up
+			for _, index := range pending {
+				results[index].Err = retryError.UnwrapAgain(batchErr)
+			}
+			return batchErr
+		}
+		var stillPending []int
+		var retryErr error
+		for i, index := range pending {
+			results[index].Err = retryError.UnwrapAgain(itemErrs[i])
+			if retryError.IsAgain(itemErrs[i]) {
+				stillPending = append(stillPending, index)
+				retryErr = itemErrs[i]
+			}
+		}
+		pending = stillPending
+		return retryErr
+	})
+	for _, index := range pending {
+		if !attempted {
+			results[index].Err = loopErr
+		} else {
+			results[index].Err = markedLike(ctx, loopErr, results[index].Err)
+		}
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed == 0 && loopErr == nil {
+		return results, nil
+	}
+	return results, &BatchError{
+		Failed: failed,
+		Total:  len(items),
+		Err:    loopErr,
+	}
+}
+
+// markedLike marks itemErr the same way the loop marked its own error when it stopped retrying
+func markedLike(ctx context.Context, loopErr, itemErr error) error {
+	switch {
+	case errors.Is(loopErr, retryError.ErrAttemptsExhausted):
+		return retryError.AttemptsExhausted(itemErr)
+	case errors.Is(loopErr, retryError.ErrBudgetExhausted):
+		return retryError.BudgetExhausted(itemErr)
+	case errors.Is(loopErr, retryError.ErrDeadlineTooClose):
+		return retryError.DeadlineTooClose(itemErr)
+	case ctx.Err() != nil:
+		return retryError.ContextDone(ctx.Err(), itemErr)
+	default:
+		return itemErr
+	}
+}
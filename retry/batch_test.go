@@ -0,0 +1,172 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+// batchFailures fails each item as many times as given, then succeeds. Items not listed always succeed
+type batchFailures struct {
+	retryable map[string]int
+	permanent map[string]bool
+	calls     [][]string
+}
+
+func (b *batchFailures) callback(_ context.Context, pending []string) ([]error, error) {
+	b.calls = append(b.calls, append([]string{}, pending...))
+	results := make([]error, len(pending))
+	for i, item := range pending {
+		switch {
+		case b.permanent[item]:
+			results[i] = retryMocks.ErrThatCannotBeRetried
+		case b.retryable[item] > 0:
+			b.retryable[item]--
+			results[i] = retryMocks.ErrRetry
+		}
+	}
+	return results, nil
+}
+
+var _ = Describe("RetryBatch", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		items  []string
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+		items = []string{"a", "b", "c", "d"}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("every item succeeds", func() {
+		It("calls the callback once", func() {
+			failures := &batchFailures{}
+			results, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, failures.callback)
+			Expect(err).Should(BeNil())
+			Expect(failures.calls).Should(Equal([][]string{items}))
+			for i, result := range results {
+				Expect(result.Item).Should(Equal(items[i]))
+				Expect(result.Err).Should(BeNil())
+				Expect(result.Attempts).Should(BeNumerically("==", 1))
+			}
+		})
+	})
+	When("some items fail with retryable errors", func() {
+		It("retries only those items", func() {
+			failures := &batchFailures{retryable: map[string]int{"b": 2, "d": 1}}
+			results, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, failures.callback)
+			Expect(err).Should(BeNil())
+			Expect(failures.calls).Should(Equal([][]string{
+				{"a", "b", "c", "d"},
+				{"b", "d"},
+				{"b"},
+			}))
+			Expect(results[1].Attempts).Should(BeNumerically("==", 3))
+			Expect(results[3].Attempts).Should(BeNumerically("==", 2))
+		})
+	})
+	When("an item fails with an error that cannot be retried", func() {
+		It("stops trying that item and reports it", func() {
+			failures := &batchFailures{
+				retryable: map[string]int{"a": 1},
+				permanent: map[string]bool{"c": true},
+			}
+			results, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, failures.callback)
+			var batchErr *retry.BatchError
+			Expect(errors.As(err, &batchErr)).Should(BeTrue())
+			Expect(batchErr.Failed).Should(Equal(1))
+			Expect(batchErr.Total).Should(Equal(4))
+			Expect(batchErr.Err).Should(BeNil())
+			Expect(failures.calls[1]).Should(Equal([]string{"a"}))
+			Expect(results[0].Err).Should(BeNil())
+			Expect(results[2].Err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+			Expect(results[2].Attempts).Should(BeNumerically("==", 1))
+		})
+	})
+	When("attempts are exhausted", func() {
+		It("marks the items that were still being retried", func() {
+			failures := &batchFailures{retryable: map[string]int{"b": 10}}
+			results, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, failures.callback)
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(results[0].Err).Should(BeNil())
+			Expect(results[1].Err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(results[1].Err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(results[1].Attempts).Should(BeNumerically("==", 3))
+		})
+	})
+	When("the whole batch fails", func() {
+		It("applies the error to every pending item", func() {
+			calls := 0
+			results, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, func(_ context.Context, pending []string) ([]error, error) {
+				calls++
+				if calls == 1 {
+					return nil, retryMocks.ErrRetry
+				}
+				return make([]error, len(pending)), nil
+			})
+			Expect(err).Should(BeNil())
+			Expect(calls).Should(Equal(2))
+			Expect(results[0].Attempts).Should(BeNumerically("==", 2))
+		})
+		It("stops if the error cannot be retried", func() {
+			results, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, func(_ context.Context, _ []string) ([]error, error) {
+				return nil, retryMocks.ErrThatCannotBeRetried
+			})
+			Expect(err).Should(MatchError(retryMocks.ErrThatCannotBeRetried))
+			Expect(results[3].Err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+		})
+		It("retries the batch if the Classifier says so", func() {
+			calls := 0
+			strategy := retry.NewUpTo(0, 3)
+			strategy.Classifier = func(err error) retryClassify.Decision {
+				return retryClassify.Decision{Retry: errors.Is(err, retryMocks.ErrThatCannotBeRetried)}
+			}
+			results, err := retry.RetryBatch(ctx, strategy, items, func(_ context.Context, pending []string) ([]error, error) {
+				calls++
+				if calls == 1 {
+					return nil, retryMocks.ErrThatCannotBeRetried
+				}
+				return make([]error, len(pending)), nil
+			})
+			Expect(err).Should(BeNil())
+			Expect(calls).Should(Equal(2))
+			for _, result := range results {
+				Expect(result.Err).Should(BeNil())
+				Expect(result.Attempts).Should(BeNumerically("==", 2))
+			}
+		})
+		It("retries the batch if the attempt timed out", func() {
+			strategy := retry.NewUpTo(0, 2)
+			strategy.PerAttemptTimeout = time.Millisecond
+			var calls [][]string
+			results, err := retry.RetryBatch(ctx, strategy, items, func(ctx context.Context, pending []string) ([]error, error) {
+				calls = append(calls, pending)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(err).Should(MatchError(context.DeadlineExceeded))
+			Expect(calls).Should(Equal([][]string{items, items}))
+			for _, result := range results {
+				Expect(result.Err).Should(MatchError(retryError.ErrAttemptsExhausted))
+				Expect(result.Attempts).Should(BeNumerically("==", 2))
+			}
+		})
+		It("fails if the results do not match the items", func() {
+			_, err := retry.RetryBatch(ctx, retry.NewUpTo(0, 3), items, func(_ context.Context, _ []string) ([]error, error) {
+				return nil, nil
+			})
+			Expect(err).Should(MatchError(retry.ErrBatchResults))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"time"
+)
+
+// Builder combines a Backoff with StopPolicies to make a Composed strategy:
+//
+//	strategy := retry.NewBuilder(retry.NewLinearBackoff(10*time.Millisecond, 1.0)).
+//	  MaxWait(time.Second).
+//	  StopWhen(retry.MaxElapsed(time.Minute)).
+//	  Build()
+type Builder struct {
+	backoff Backoff
+	stop    AnyOf
+	jitter  Jitter
+	options retryLoop.Options
+}
+
+func NewBuilder(backoff Backoff) *Builder {
+	return &Builder{
+		backoff: backoff,
+	}
+}
+
+// MaxWait caps each wait of the Backoff, see CappedBackoff
+func (b *Builder) MaxWait(maxWaitBetweenAttempts time.Duration) *Builder {
+	b.backoff = NewCappedBackoff(b.backoff, maxWaitBetweenAttempts)
+	return b
+}
+
+// StopWhen adds policies to stop retrying. The strategy stops when any of the policies would stop.
+// If no policies are added, the strategy never gives up on its own
+func (b *Builder) StopWhen(policies ...StopPolicy) *Builder {
+	b.stop = append(b.stop, policies...)
+	return b
+}
+
+// Jitter randomizes each wait
+func (b *Builder) Jitter(jitter Jitter) *Builder {
+	b.jitter = jitter
+	return b
+}
+
+// Options tune how each attempt is made
+func (b *Builder) Options(options retryLoop.Options) *Builder {
+	b.options = options
+	return b
+}
+
+// Build creates the strategy. The Builder may be used again afterwards without affecting the returned strategy
+func (b *Builder) Build() *Composed {
+	var stop StopPolicy
+	if len(b.stop) != 0 {
+		stop = append(AnyOf{}, b.stop...)
+	}
+	return &Composed{
+		Backoff: b.backoff,
+		Stop:    stop,
+		Jitter:  b.jitter,
+		Options: b.options,
+	}
+}
@@ -0,0 +1,83 @@
+package retry_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+// retryWithFakeClock runs the retry and advances the clock through each wait until the retry returns
+func retryWithFakeClock(clock *retryMocks.FakeClock, retry func() error) (err error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- retry()
+	}()
+	for {
+		select {
+		case err = <-done:
+			return
+		default:
+			clock.AdvanceToNextSleeper()
+			time.Sleep(10 * time.Microsecond)
+		}
+	}
+}
+
+var _ = Describe("Clock", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		clock  *retryMocks.FakeClock
+		start  time.Time
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+		start = time.Date(2021, 11, 5, 0, 0, 0, 0, time.UTC)
+		clock = retryMocks.NewFakeClock(start)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("ExponentialMaxWaitUpTo uses a fake clock", func() {
+		It("waits exactly the capped exponential time without really waiting", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry, // 1 hour
+				retryMocks.ErrRetry, // 2 hours
+				retryMocks.ErrRetry, // 4 hours
+				retryMocks.ErrRetry, // 8 hours (5), total 12
+				retryMocks.ErrRetry, // 16 hours (5), total 17
+				retryError.StopSuccess,
+			}}
+			subject := retry.NewExponentialMaxWaitUpTo(1*time.Hour, 1.0, 10, 5*time.Hour)
+			subject.Clock = clock
+			elapsed := retryMocks.DurationElapsed(func() {
+				err := retryWithFakeClock(clock, func() error {
+					return subject.Retry(ctx, mock.Generator())
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+			Expect(clock.Now().Sub(start)).Should(Equal(17 * time.Hour))
+			Expect(elapsed).Should(BeNumerically("<", 500*time.Millisecond))
+		})
+	})
+	When("a stop policy limits the elapsed time", func() {
+		It("uses the fake clock to measure the elapsed time", func() {
+			subject := retry.NewBuilder(retry.NewConstantBackoff(1 * time.Hour)).
+				StopWhen(retry.MaxElapsed(3 * time.Hour)).
+				Build()
+			subject.Clock = clock
+			err := retryWithFakeClock(clock, func() error {
+				return subject.Retry(ctx, func() error {
+					return retryMocks.ErrRetry
+				})
+			})
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(clock.Now().Sub(start)).Should(Equal(3 * time.Hour))
+		})
+	})
+})
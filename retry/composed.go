@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"context"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"time"
+)
+
+// Composed retries by waiting according to Backoff until Stop says to give up.
+// All the other strategies in this package, except Hedged and Skip, are built on Composed. Use a Builder to make one fluently.
+// A nil Backoff does not wait between attempts. A nil Stop retries until the callback succeeds, returns a
+// non-retryable error, or the context is done
+type Composed struct {
+	retryStrategy
+	Backoff Backoff
+	Stop    StopPolicy
+
+	// Jitter randomizes each wait, nil means no jitter
+	Jitter Jitter
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
+}
+
+func NewComposed(backoff Backoff, stop StopPolicy) *Composed {
+	return &Composed{
+		Backoff: backoff,
+		Stop:    stop,
+	}
+}
+
+func (c *Composed) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *Composed) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	waits := jitteredWait{jitter: c.Jitter}
+	clock := retrySleep.OrSystem(c.Clock)
+	startedAt := clock.Now()
+	progress := Progress{}
+	return retryLoop.UntilAttempt(ctx, func(ctx context.Context, attempt retryLoop.AttemptInfo) error {
+		// the loop may have waited for a hint from the error instead of NextWait, so count what it really waited
+		progress.TotalWait += attempt.Waited
+		waits.waited(attempt.Waited)
+		return cb(ctx, attempt)
+	}, func(_ uint64) time.Duration {
+		// the wait was already calculated when deciding whether to continue
+		return progress.NextWait
+	}, func(timesAttempted uint64) bool {
+		progress.Attempts = timesAttempted
+		progress.Elapsed = clock.Now().Sub(startedAt)
+		progress.NextWait = waits.delay(c.Backoff, timesAttempted-1)
+		return c.Stop == nil || !c.Stop.ShouldStop(progress)
+	}, c.Options)
+}
+
+// String is the spec of the strategy, see Parse
+func (c *Composed) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *Composed) spec() *specWriter {
+	return newSpecWriter("composed").
+		backoff(c.Backoff).
+		stop(c.Stop).
+		jitter(c.Jitter).
+		options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse. It fails if the Backoff, Stop or Jitter are from another
+// package, or if Stop has more than one policy of the same type
+func (c *Composed) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a Composed
+func (c *Composed) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *Composed) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *Composed) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
+}
@@ -0,0 +1,127 @@
+package retry_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("Composed", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("linear waits are capped without an attempt limit", func() {
+		var (
+			mock    *retryMocks.Callback
+			subject *retry.Composed
+		)
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry, // 1, total 1
+				retryMocks.ErrRetry, // 2, total 3
+				retryMocks.ErrRetry, // 3 (2), total 5
+				retryMocks.ErrRetry, // 4 (2), total 7
+				retryError.StopSuccess,
+			}}
+			subject = retry.NewBuilder(retry.NewLinearBackoff(1*timeUnit, 1.0)).
+				MaxWait(2 * timeUnit).
+				Build()
+		})
+		It("takes the appropriate amount of time", func() {
+			elapsed := retryMocks.DurationElapsed(func() {
+				err := subject.Retry(ctx, mock.Generator())
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+			Expect(elapsed).Should(BeNumerically(">", 7*timeUnit))
+			Expect(elapsed).Should(BeNumerically("<", 17*timeUnit))
+		})
+	})
+	When("total wait is limited", func() {
+		var (
+			subject *retry.Composed
+		)
+		BeforeEach(func() {
+			subject = retry.NewBuilder(retry.NewConstantBackoff(2 * timeUnit)).
+				StopWhen(retry.MaxTotalWait(7 * timeUnit)).
+				Build()
+		})
+		It("stops before exceeding the total wait", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry, // total 2
+				retryMocks.ErrRetry, // total 4
+				retryMocks.ErrRetry, // total 6
+				retryMocks.ErrRetry, // total 8 would exceed, stop
+				retryError.StopSuccess,
+			}}
+			err := subject.Retry(ctx, mock.Generator())
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(mock.TimesRun()).Should(Equal(4))
+		})
+	})
+	When("errors hint how long to wait", func() {
+		It("counts the hinted waits towards the total wait", func() {
+			var totalWaits []time.Duration
+			subject := retry.NewComposed(retry.NewConstantBackoff(1*timeUnit), stopFunc(func(progress retry.Progress) bool {
+				totalWaits = append(totalWaits, progress.TotalWait)
+				return retry.MaxTotalWait(25 * timeUnit).ShouldStop(progress)
+			}))
+			attempts := 0
+			err := subject.Retry(ctx, func() error {
+				attempts++
+				return retryError.AgainAfter(retryMocks.ErrRetryReason, 10*timeUnit)
+			})
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(attempts).Should(Equal(4))
+			Expect(totalWaits).Should(Equal([]time.Duration{0, 10 * timeUnit, 20 * timeUnit, 30 * timeUnit}))
+		})
+	})
+	When("any policy stops", func() {
+		It("stops at the first limit reached", func() {
+			subject := retry.NewBuilder(nil).
+				StopWhen(retry.MaxAttempts(3), retry.MaxElapsed(time.Hour)).
+				Build()
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryMocks.ErrRetry,
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := subject.Retry(ctx, mock.Generator())
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(mock.TimesRun()).Should(Equal(3))
+		})
+	})
+	When("no stop policy", func() {
+		It("retries until success", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := retry.NewComposed(nil, nil).Retry(ctx, mock.Generator())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mock.TimesRun()).Should(Equal(3))
+		})
+	})
+})
+
+// stopFunc is a StopPolicy that calls the function
+type stopFunc func(progress retry.Progress) bool
+
+func (f stopFunc) ShouldStop(progress retry.Progress) bool {
+	return f(progress)
+}
@@ -0,0 +1,38 @@
+package retry
+
+import (
+	"context"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"sync"
+)
+
+// ValueCallbackFunc is like retryLoop.CallbackFunc, but also produces a value when it succeeds.
+// ctx is the context for the current attempt, see retryLoop.AttemptCallbackFunc.
+// The rules for the returned error are the same as for retryLoop.CallbackFunc
+type ValueCallbackFunc[T any] func(ctx context.Context) (value T, err error)
+
+// Do retries callback using strategy and returns the value from the attempt that succeeded.
+// This removes the need to capture results in variables outside of your callback.
+// If the retry fails, the zero value of T is returned along with the error.
+// With strategies that make attempts in parallel, such as Hedged, the value of the first attempt to succeed is kept
+func Do[T any](ctx context.Context, strategy Strategy, callback ValueCallbackFunc[T]) (value T, err error) {
+	var mu sync.Mutex
+	succeeded := false
+	err = strategy.RetryAttempt(ctx, func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+		attemptValue, attemptErr := callback(ctx)
+		if attemptErr == nil {
+			mu.Lock()
+			defer mu.Unlock()
+			if !succeeded {
+				value = attemptValue
+				succeeded = true
+			}
+		}
+		return attemptErr
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
@@ -0,0 +1,77 @@
+package retry_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"sync/atomic"
+	"time"
+)
+
+var _ = Describe("Do", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("succeeds after retrying", func() {
+		It("returns the value of the successful attempt", func() {
+			tries := 0
+			value, err := retry.Do(ctx, retry.NewUpTo(0, 10), func(_ context.Context) (int, error) {
+				tries++
+				if tries < 3 {
+					return tries, retryMocks.ErrRetry
+				}
+				return tries, retryError.StopSuccess
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(Equal(3))
+		})
+	})
+	When("retries exhausted", func() {
+		It("returns the zero value", func() {
+			value, err := retry.Do(ctx, retry.NewUpTo(0, 2), func(_ context.Context) (string, error) {
+				return "partial", retryMocks.ErrRetry
+			})
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(value).Should(BeEmpty())
+		})
+	})
+	When("hedged", func() {
+		It("returns once every attempt has returned", func() {
+			var running int32
+			value, err := retry.Do(ctx, retry.NewHedged(1*time.Millisecond, 2, 0), func(_ context.Context) (int, error) {
+				attempt := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				// both attempts succeed, ignoring that the loser's context is canceled
+				time.Sleep(5 * time.Millisecond)
+				return int(attempt), retryError.StopSuccess
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(BeNumerically(">", 0))
+			Expect(atomic.LoadInt32(&running)).Should(BeZero())
+		})
+	})
+	When("skipped", func() {
+		It("does not call the callback", func() {
+			wasCalled := false
+			value, err := retry.Do(ctx, retry.Skip, func(_ context.Context) (int, error) {
+				wasCalled = true
+				return 1, nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(BeZero())
+			Expect(wasCalled).Should(BeFalse())
+		})
+	})
+})
@@ -3,7 +3,6 @@ package retry
 import (
 	"context"
 	"github.com/wojnosystems/go-retry/retryLoop"
-	"github.com/wojnosystems/go-retry/retrySleep"
 	"time"
 )
 
@@ -15,6 +14,12 @@ type Exponential struct {
 	retryStrategy
 	InitialWaitBetweenAttempts time.Duration
 	GrowthFactor               float64
+
+	// Jitter randomizes each wait, nil means no jitter
+	Jitter Jitter
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
 }
 
 func NewExponential(
@@ -28,8 +33,66 @@ func NewExponential(
 }
 
 func (c *Exponential) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
-	return retryLoop.Forever(ctx, cb, func(i uint64) {
-		sleepTime := exponentialSleepTime(c.InitialWaitBetweenAttempts, c.GrowthFactor, i)
-		retrySleep.WithContext(ctx, sleepTime)
-	})
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *Exponential) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	return c.composed().RetryAttempt(ctx, cb)
+}
+
+// composed is the equivalent Composed strategy
+func (c *Exponential) composed() *Composed {
+	return &Composed{
+		Backoff: &ExponentialBackoff{
+			InitialWaitBetweenAttempts: c.InitialWaitBetweenAttempts,
+			GrowthFactor:               c.GrowthFactor,
+		},
+		Jitter:  c.Jitter,
+		Options: c.Options,
+	}
+}
+
+// Schedule plans at most n waits, see Plan
+func (c *Exponential) Schedule(n int) Plan {
+	return c.composed().Schedule(n)
+}
+
+// Plan plans every wait the strategy would make, see Planner
+func (c *Exponential) Plan() Plan {
+	return c.composed().Plan()
+}
+
+// String is the spec of the strategy, see Parse
+func (c *Exponential) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *Exponential) spec() *specWriter {
+	return newSpecWriter("exponential").
+		duration("initial", c.InitialWaitBetweenAttempts).
+		float("growth", c.GrowthFactor).
+		jitter(c.Jitter).
+		options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse. It fails if the strategy has a Jitter from another package
+func (c *Exponential) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a Exponential
+func (c *Exponential) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *Exponential) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *Exponential) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
 }
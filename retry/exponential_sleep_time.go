@@ -6,6 +6,6 @@ import (
 )
 
 func exponentialSleepTime(initial time.Duration, growthFactor float64, iteration uint64) time.Duration {
-	return time.Duration(
+	return saturatedDuration(
 		float64(initial) * math.Pow(1.0+growthFactor, float64(iteration)))
 }
@@ -2,6 +2,7 @@ package retry
 
 import (
 	"github.com/onsi/gomega"
+	"math"
 	"strconv"
 	"testing"
 	"time"
@@ -26,6 +27,12 @@ func Test_exponentialSleepTime(t *testing.T) {
 			iterations:   5,
 			expected:     243 * timeUnit,
 		},
+		{
+			initial:      50 * timeUnit,
+			growthFactor: 1.0,
+			iterations:   100,
+			expected:     math.MaxInt64,
+		},
 	}
 
 	for caseIndex, c := range cases {
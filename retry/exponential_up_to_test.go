@@ -53,5 +53,20 @@ var _ = Describe("ExponentialUpTo", func() {
 				Expect(elapsed).Should(BeNumerically("<", 41*timeUnit))
 			})
 		})
+		When("full jitter picks the shortest wait", func() {
+			var (
+				subject *retry.ExponentialUpTo
+			)
+			BeforeEach(func() {
+				subject = retry.NewExponentialUpTo(1*timeUnit, 1.0, 6)
+				subject.Jitter = retry.NewFullJitter(&retryMocks.Random{Fraction: 0})
+			})
+			It("does not wait", func() {
+				elapsed := retryMocks.DurationElapsed(func() {
+					_ = subject.Retry(ctx, mock.Generator())
+				})
+				Expect(elapsed).Should(BeNumerically("<", 10*timeUnit))
+			})
+		})
 	})
 })
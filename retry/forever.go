@@ -3,7 +3,6 @@ package retry
 import (
 	"context"
 	"github.com/wojnosystems/go-retry/retryLoop"
-	"github.com/wojnosystems/go-retry/retrySleep"
 	"time"
 )
 
@@ -11,6 +10,12 @@ import (
 type Forever struct {
 	retryStrategy
 	WaitBetweenAttempts time.Duration
+
+	// Jitter randomizes each wait, nil means no jitter
+	Jitter Jitter
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
 }
 
 func NewForever(
@@ -22,7 +27,64 @@ func NewForever(
 }
 
 func (c *Forever) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
-	return retryLoop.Forever(ctx, cb, func(_ uint64) {
-		retrySleep.WithContext(ctx, c.WaitBetweenAttempts)
-	})
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *Forever) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	return c.composed().RetryAttempt(ctx, cb)
+}
+
+// composed is the equivalent Composed strategy
+func (c *Forever) composed() *Composed {
+	return &Composed{
+		Backoff: &ConstantBackoff{
+			WaitBetweenAttempts: c.WaitBetweenAttempts,
+		},
+		Jitter:  c.Jitter,
+		Options: c.Options,
+	}
+}
+
+// Schedule plans at most n waits, see Plan
+func (c *Forever) Schedule(n int) Plan {
+	return c.composed().Schedule(n)
+}
+
+// Plan plans every wait the strategy would make, see Planner
+func (c *Forever) Plan() Plan {
+	return c.composed().Plan()
+}
+
+// String is the spec of the strategy, see Parse
+func (c *Forever) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *Forever) spec() *specWriter {
+	return newSpecWriter("constant").
+		duration("wait", c.WaitBetweenAttempts).
+		jitter(c.Jitter).
+		options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse. It fails if the strategy has a Jitter from another package
+func (c *Forever) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a Forever
+func (c *Forever) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *Forever) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *Forever) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
 }
@@ -0,0 +1,296 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"sync"
+	"time"
+)
+
+// Hedged makes speculative attempts in parallel to cut tail latency, usually for idempotent reads.
+// The first attempt starts right away. Each time Delay passes without a result, another attempt is started alongside
+// the ones still running, until MaxAttempts have been started. If an attempt returns a retryable error, the next
+// attempt starts right away instead of after Delay.
+// The first attempt to succeed wins and the contexts of the others are canceled. If an attempt returns an error that
+// is not retryable, the others are canceled and the error is returned. Either way, Retry only returns once every
+// attempt it started has returned, so callbacks should return soon after their context is done. If every attempt returns a retryable error,
+// the last one is returned wrapped so that errors.Is matches retryError.ErrAttemptsExhausted.
+// Like retrySleep.WithContext, waiting for Delay never outlives ctx: once ctx is done, the attempts are canceled and
+// its error is returned. If ctx is already done, no attempt is made.
+// The callback is called from several goroutines at once and must be safe for that.
+// Of the Options, PerAttemptTimeout, Classifier, Tracer, Observer, Clock and Budget are used. The Observer is only
+// called from the goroutine that called Retry. The Budget is consulted before starting each attempt after the first
+type Hedged struct {
+	retryStrategy
+	// Delay is how long to wait for a result before starting another attempt
+	Delay time.Duration
+
+	// MaxAttempts is how many attempts may be started in total. At least one attempt is always made
+	MaxAttempts uint
+
+	// MaxInFlight caps how many attempts run at once, 0 means only MaxAttempts limits them
+	MaxInFlight uint
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
+}
+
+func NewHedged(
+	delay time.Duration,
+	maxAttempts uint,
+	maxInFlight uint,
+) *Hedged {
+	return &Hedged{
+		Delay:       delay,
+		MaxAttempts: maxAttempts,
+		MaxInFlight: maxInFlight,
+	}
+}
+
+func (c *Hedged) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *Hedged) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	_, err = c.RetryWinner(ctx, cb)
+	return
+}
+
+// RetryWinner is like RetryAttempt, but also returns the attempt that succeeded.
+// If no attempt succeeded, winner's Number is 0
+func (c *Hedged) RetryWinner(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (winner retryLoop.AttemptInfo, err error) {
+	h := newHedge(ctx, c, cb)
+	defer h.stop()
+	return h.run()
+}
+
+// Schedule plans at most n waits. Hedged attempts don't wait for each other to fail, so each wait is the Delay before
+// the next attempt is started, and the Total is when the last attempt starts if none returns in the meantime.
+// With MaxInFlight set, attempts may also wait for one in flight to return, which can't be planned
+func (c *Hedged) Schedule(n int) Plan {
+	plan := Plan{Attempts: 1}
+	for plan.Attempts < uint64(c.MaxAttempts) {
+		if len(plan.Waits) >= n {
+			plan.Truncated = true
+			break
+		}
+		plan.addWait(c.Delay)
+		plan.Attempts++
+	}
+	return plan
+}
+
+// Plan plans every wait the strategy would make, see Schedule
+func (c *Hedged) Plan() Plan {
+	return c.Schedule(MaxPlannedWaits)
+}
+
+// String is the spec of the strategy, see Parse
+func (c *Hedged) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *Hedged) spec() *specWriter {
+	w := newSpecWriter("hedged").
+		duration("delay", c.Delay).
+		uint("max_attempts", c.MaxAttempts)
+	if c.MaxInFlight != 0 {
+		w.uint("max_in_flight", c.MaxInFlight)
+	}
+	return w.options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse
+func (c *Hedged) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a Hedged
+func (c *Hedged) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *Hedged) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *Hedged) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
+}
+
+// hedgeObserver returns the Observer to notify, which is the Observer in options and the one added to ctx by
+// retryLoop.WithObserver, like the loop notifies
+func hedgeObserver(ctx context.Context, options retryLoop.Options) retryLoop.Observer {
+	observers := retryLoop.Observers{}
+	for _, observer := range []retryLoop.Observer{options.Observer, retryLoop.ObserverFrom(ctx)} {
+		if observer != nil {
+			observers = append(observers, observer)
+		}
+	}
+	return observers
+}
+
+// hedgedResult is what an attempt returned
+type hedgedResult struct {
+	attempt retryLoop.AttemptInfo
+	err     error
+}
+
+// hedge is the state of a single call to Hedged.RetryWinner
+type hedge struct {
+	*Hedged
+	ctx      context.Context
+	cancel   context.CancelFunc
+	cb       retryLoop.AttemptCallbackFunc
+	clock    retrySleep.Clock
+	observer retryLoop.Observer
+	results  chan hedgedResult
+
+	startedAt     time.Time
+	maxAttempts   uint
+	started       uint
+	inFlight      uint
+	budgetRefused bool
+	previousErr   error
+	lastAttempt   retryLoop.AttemptInfo
+	nextHedge     <-chan time.Time
+
+	// running counts the attempts that have not returned yet
+	running sync.WaitGroup
+}
+
+func newHedge(ctx context.Context, c *Hedged, cb retryLoop.AttemptCallbackFunc) *hedge {
+	h := &hedge{
+		Hedged:      c,
+		cb:          cb,
+		clock:       retrySleep.OrSystem(c.Clock),
+		observer:    hedgeObserver(ctx, c.Options),
+		maxAttempts: c.MaxAttempts,
+	}
+	if h.maxAttempts == 0 {
+		h.maxAttempts = 1
+	}
+	// every attempt sends exactly one result, so attempts still running after run returns never block
+	h.results = make(chan hedgedResult, h.maxAttempts)
+	h.ctx, h.cancel = context.WithCancel(ctx)
+	h.startedAt = h.clock.Now()
+	return h
+}
+
+// run starts attempts and collects their results until one wins or there's no point continuing
+func (h *hedge) run() (winner retryLoop.AttemptInfo, err error) {
+	if h.Budget != nil {
+		h.Budget.Requested()
+	}
+	if h.ctx.Err() != nil {
+		return winner, h.contextDone(h.ctx.Err())
+	}
+	h.start(0)
+	for {
+		if h.ctx.Err() != nil {
+			return winner, h.contextDone(h.ctx.Err())
+		}
+		if h.inFlight == 0 {
+			// every attempt failed and no more may be started
+			if h.budgetRefused {
+				return winner, h.giveUp(retryLoop.GiveUpBudgetExhausted, retryError.BudgetExhausted(h.previousErr))
+			}
+			return winner, h.giveUp(retryLoop.GiveUpAttemptsExhausted, retryError.AttemptsExhausted(h.previousErr))
+		}
+		select {
+		case <-h.ctx.Done():
+			// checked at the top of the loop
+		case <-h.nextHedge:
+			h.nextHedge = nil
+			h.tryStart(h.Delay)
+		case result := <-h.results:
+			h.inFlight--
+			h.lastAttempt = result.attempt
+			h.observer.OnAttemptEnd(result.attempt, result.err, result.attempt.Duration)
+			if result.err == retryError.StopSuccess {
+				h.observer.OnSuccess(result.attempt)
+				return result.attempt, nil
+			}
+			if !retryError.IsAgain(result.err) {
+				if h.ctx.Err() != nil && errors.Is(result.err, h.ctx.Err()) {
+					return winner, h.contextDone(result.err)
+				}
+				return winner, h.giveUp(retryLoop.GiveUpNotRetryable, result.err)
+			}
+			h.previousErr = retryError.UnwrapAgain(result.err)
+			h.tryStart(0)
+		}
+	}
+}
+
+// tryStart starts another attempt if MaxAttempts, MaxInFlight and the Budget allow it. waited is how long was
+// waited for it, see retryLoop.AttemptInfo
+func (h *hedge) tryStart(waited time.Duration) {
+	if h.started >= h.maxAttempts || h.budgetRefused || h.ctx.Err() != nil {
+		return
+	}
+	if h.MaxInFlight != 0 && h.inFlight >= h.MaxInFlight {
+		return
+	}
+	if h.Budget != nil && !h.Budget.Withdraw() {
+		h.budgetRefused = true
+		return
+	}
+	h.start(waited)
+}
+
+// start starts the next attempt in its own goroutine and schedules the next hedge
+func (h *hedge) start(waited time.Duration) {
+	h.started++
+	h.inFlight++
+	attempt := retryLoop.AttemptInfo{
+		Number:      uint64(h.started),
+		Elapsed:     h.clock.Now().Sub(h.startedAt),
+		PreviousErr: h.previousErr,
+		Waited:      waited,
+	}
+	h.lastAttempt = attempt
+	h.observer.OnAttemptStart(attempt)
+	h.running.Add(1)
+	go func() {
+		defer h.running.Done()
+		attemptStartedAt := h.clock.Now()
+		err := retryLoop.CallAttempt(h.ctx, h.cb, attempt, h.Options)
+		ended := attempt
+		ended.Err = retryError.UnwrapAgain(err)
+		ended.Duration = h.clock.Now().Sub(attemptStartedAt)
+		h.results <- hedgedResult{
+			attempt: ended,
+			err:     err,
+		}
+	}()
+	if h.started < h.maxAttempts {
+		h.nextHedge = h.clock.After(h.Delay)
+	}
+}
+
+// stop cancels the attempts still running and waits for them to return, so that none of them outlives Retry
+func (h *hedge) stop() {
+	h.cancel()
+	h.running.Wait()
+}
+
+// contextDone gives up because ctx is done, keeping the last retryable error, if any
+func (h *hedge) contextDone(ctxErr error) error {
+	if h.previousErr != nil {
+		return h.giveUp(retryLoop.GiveUpContextDone, retryError.ContextDone(ctxErr, h.previousErr))
+	}
+	return h.giveUp(retryLoop.GiveUpContextDone, ctxErr)
+}
+
+func (h *hedge) giveUp(reason retryLoop.GiveUpReason, err error) error {
+	h.observer.OnGiveUp(h.lastAttempt, reason, err)
+	return err
+}
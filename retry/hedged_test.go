@@ -0,0 +1,192 @@
+package retry_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+// hedgedCallback calls the behavior for each attempt by number and reports each attempt as it starts
+type hedgedCallback struct {
+	behaviors map[uint64]func(ctx context.Context) error
+	started   chan uint64
+}
+
+func newHedgedCallback(behaviors map[uint64]func(ctx context.Context) error) *hedgedCallback {
+	return &hedgedCallback{
+		behaviors: behaviors,
+		started:   make(chan uint64, 10),
+	}
+}
+
+func (h *hedgedCallback) callback(ctx context.Context, attempt retryLoop.AttemptInfo) error {
+	h.started <- attempt.Number
+	return h.behaviors[attempt.Number](ctx)
+}
+
+func blocksUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func succeeds(_ context.Context) error {
+	return retryError.StopSuccess
+}
+
+func failsRetryably(_ context.Context) error {
+	return retryMocks.ErrRetry
+}
+
+type hedgedOutcome struct {
+	winner retryLoop.AttemptInfo
+	err    error
+}
+
+var _ = Describe("Hedged", func() {
+	var (
+		ctx      context.Context
+		cancel   context.CancelFunc
+		clock    *retryMocks.FakeClock
+		observer *retryMocks.Observer
+		subject  *retry.Hedged
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+		clock = retryMocks.NewFakeClock(time.Date(2021, 11, 5, 0, 0, 0, 0, time.UTC))
+		observer = &retryMocks.Observer{}
+		subject = retry.NewHedged(10*time.Millisecond, 3, 0)
+		subject.Clock = clock
+		subject.Observer = observer
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	run := func(cb *hedgedCallback) <-chan hedgedOutcome {
+		done := make(chan hedgedOutcome, 1)
+		go func() {
+			winner, err := subject.RetryWinner(ctx, cb.callback)
+			done <- hedgedOutcome{winner: winner, err: err}
+		}()
+		return done
+	}
+
+	When("the first attempt is fast", func() {
+		It("does not hedge", func() {
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{1: succeeds})
+			outcome := <-run(cb)
+			Expect(outcome.err).Should(BeNil())
+			Expect(outcome.winner.Number).Should(BeNumerically("==", 1))
+			Expect(cb.started).Should(HaveLen(1))
+		})
+	})
+	When("the first attempt is slow", func() {
+		It("starts another after the delay and the fastest wins", func() {
+			firstCanceled := make(chan struct{})
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{
+				1: func(ctx context.Context) error {
+					err := blocksUntilDone(ctx)
+					close(firstCanceled)
+					return err
+				},
+				2: succeeds,
+			})
+			done := run(cb)
+			Expect(<-cb.started).Should(BeNumerically("==", 1))
+			clock.WaitForSleepers(1)
+			clock.Advance(10 * time.Millisecond)
+			outcome := <-done
+			Expect(outcome.err).Should(BeNil())
+			Expect(outcome.winner.Number).Should(BeNumerically("==", 2))
+			Eventually(firstCanceled).Should(BeClosed())
+			Expect(observer.Events()).Should(ContainElement("success 2"))
+		})
+	})
+	When("an attempt fails with a retryable error", func() {
+		It("starts the next attempt without waiting", func() {
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{
+				1: failsRetryably,
+				2: succeeds,
+			})
+			outcome := <-run(cb)
+			Expect(outcome.err).Should(BeNil())
+			Expect(outcome.winner.Number).Should(BeNumerically("==", 2))
+		})
+	})
+	When("every attempt fails with a retryable error", func() {
+		It("returns the last error as exhausted", func() {
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{
+				1: failsRetryably,
+				2: failsRetryably,
+				3: failsRetryably,
+			})
+			outcome := <-run(cb)
+			Expect(outcome.err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(outcome.err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(outcome.winner.Number).Should(BeZero())
+			Expect(cb.started).Should(HaveLen(3))
+		})
+	})
+	When("an attempt fails with an error that cannot be retried", func() {
+		It("cancels the others and returns the error", func() {
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{
+				1: blocksUntilDone,
+				2: func(_ context.Context) error {
+					return retryMocks.ErrThatCannotBeRetried
+				},
+			})
+			done := run(cb)
+			<-cb.started
+			clock.WaitForSleepers(1)
+			clock.Advance(10 * time.Millisecond)
+			outcome := <-done
+			Expect(outcome.err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+		})
+	})
+	When("in-flight attempts are capped", func() {
+		BeforeEach(func() {
+			subject.MaxInFlight = 1
+		})
+		It("waits for a slot before hedging", func() {
+			release := make(chan struct{})
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{
+				1: func(_ context.Context) error {
+					<-release
+					return retryMocks.ErrRetry
+				},
+				2: succeeds,
+			})
+			done := run(cb)
+			<-cb.started
+			clock.WaitForSleepers(1)
+			clock.Advance(10 * time.Millisecond)
+			Consistently(cb.started, 20*time.Millisecond).ShouldNot(Receive())
+			close(release)
+			outcome := <-done
+			Expect(outcome.err).Should(BeNil())
+			Expect(outcome.winner.Number).Should(BeNumerically("==", 2))
+		})
+	})
+	When("the context is canceled", func() {
+		It("cancels the attempts and returns the context's error", func() {
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{1: blocksUntilDone})
+			done := run(cb)
+			<-cb.started
+			cancel()
+			outcome := <-done
+			Expect(outcome.err).Should(MatchError(context.Canceled))
+		})
+		It("makes no attempt if it was canceled already", func() {
+			cancel()
+			cb := newHedgedCallback(map[uint64]func(ctx context.Context) error{1: succeeds})
+			outcome := <-run(cb)
+			Expect(outcome.err).Should(MatchError(context.Canceled))
+			Expect(cb.started).Should(BeEmpty())
+			Expect(observer.Events()).Should(Equal([]string{"give up context_done"}))
+		})
+	})
+})
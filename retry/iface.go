@@ -5,6 +5,15 @@ import (
 	"github.com/wojnosystems/go-retry/retryLoop"
 )
 
-type retryStrategy interface {
+// Strategy is implemented by every retry strategy in this package, including Skip and Never.
+// Accept a Strategy wherever you want the caller to decide how something is retried
+type Strategy interface {
+	// Retry calls cb until it succeeds, returns a non-retryable error, or the strategy gives up
 	Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error)
+
+	// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+	RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error)
 }
+
+// retryStrategy is embedded in each of the strategies to mark them as implementing Strategy
+type retryStrategy = Strategy
@@ -0,0 +1,36 @@
+package retry_test
+
+import (
+	"github.com/wojnosystems/go-retry/retry"
+)
+
+// every strategy must be usable as a Strategy
+var (
+	_ retry.Strategy = retry.Skip
+	_ retry.Strategy = retry.Never
+	_ retry.Strategy = &retry.UpTo{}
+	_ retry.Strategy = &retry.Forever{}
+	_ retry.Strategy = &retry.Linear{}
+	_ retry.Strategy = &retry.LinearUpTo{}
+	_ retry.Strategy = &retry.LinearMaxWaitUpTo{}
+	_ retry.Strategy = &retry.Exponential{}
+	_ retry.Strategy = &retry.ExponentialUpTo{}
+	_ retry.Strategy = &retry.ExponentialMaxWaitUpTo{}
+	_ retry.Strategy = &retry.Hedged{}
+)
+
+// every strategy can preview its waits
+var (
+	_ retry.Planner = retry.Skip
+	_ retry.Planner = retry.Never
+	_ retry.Planner = &retry.UpTo{}
+	_ retry.Planner = &retry.Forever{}
+	_ retry.Planner = &retry.Linear{}
+	_ retry.Planner = &retry.LinearUpTo{}
+	_ retry.Planner = &retry.LinearMaxWaitUpTo{}
+	_ retry.Planner = &retry.Exponential{}
+	_ retry.Planner = &retry.ExponentialUpTo{}
+	_ retry.Planner = &retry.ExponentialMaxWaitUpTo{}
+	_ retry.Planner = &retry.Hedged{}
+	_ retry.Planner = &retry.Composed{}
+)
@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Jitter randomizes the wait computed by a strategy so that many clients failing at the same time do not all retry
+// at the same instants. Set the Jitter field on any strategy to enable it. A nil Jitter waits exactly the computed time.
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/ for the background on each mode
+type Jitter interface {
+	// Apply returns the time to actually wait.
+	// computed is the wait calculated by the strategy for this attempt.
+	// previous is the wait returned by the last call to Apply during the same Retry, or 0 if this is the first wait
+	Apply(computed, previous time.Duration) time.Duration
+}
+
+// RandomSource provides the randomness used by Jitter. *rand.Rand satisfies this interface.
+// Inject your own source to make jittered waits deterministic in tests.
+// If nil, the math/rand package-level source is used, which is safe for concurrent use
+type RandomSource interface {
+	// Int63n returns a non-negative random number in [0,n)
+	Int63n(n int64) int64
+}
+
+// globalRandom uses the math/rand package-level functions, which are safe to use from multiple goroutines
+type globalRandom struct{}
+
+func (globalRandom) Int63n(n int64) int64 {
+	return rand.Int63n(n)
+}
+
+var defaultRandomSource RandomSource = globalRandom{}
+
+// randomBetween returns a random duration in [low,high) using source, or low if the range is empty
+func randomBetween(source RandomSource, low, high time.Duration) time.Duration {
+	if high <= low {
+		return low
+	}
+	if source == nil {
+		source = defaultRandomSource
+	}
+	return low + time.Duration(source.Int63n(int64(high-low)))
+}
+
+// jitteredWait applies a Jitter to successive waits within a single Retry, remembering the previous wait
+type jitteredWait struct {
+	jitter   Jitter
+	previous time.Duration
+}
+
+// next returns the jittered version of computed
+func (j *jitteredWait) next(computed time.Duration) time.Duration {
+	if j.jitter == nil {
+		return computed
+	}
+	j.previous = j.jitter.Apply(computed, j.previous)
+	return j.previous
+}
+
+// nextCapped is like next, but the jitter is applied to the capped wait and the result will never exceed maxWait
+func (j *jitteredWait) nextCapped(computed, maxWait time.Duration) time.Duration {
+	sleepTime := minDuration(j.next(minDuration(computed, maxWait)), maxWait)
+	if j.jitter != nil {
+		j.previous = sleepTime
+	}
+	return sleepTime
+}
+
+// waited records the wait that really happened, which is not the one returned by next when the error hinted how long
+// to wait, so the next jitter is based on it
+func (j *jitteredWait) waited(actual time.Duration) {
+	if j.jitter != nil {
+		j.previous = actual
+	}
+}
+
+// delay returns the jittered wait for backoff. If backoff is capped, the jitter is kept within the cap
+func (j *jitteredWait) delay(backoff Backoff, timesWaited uint64) time.Duration {
+	if backoff == nil {
+		return 0
+	}
+	if capped, ok := backoff.(*CappedBackoff); ok {
+		return j.nextCapped(capped.Backoff.Delay(timesWaited), capped.MaxWaitBetweenAttempts)
+	}
+	return j.next(backoff.Delay(timesWaited))
+}
@@ -0,0 +1,32 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// DecorrelatedJitter grows the wait based on the previous jittered wait instead of the attempt number:
+// Wait(i) = random_between(BackoffTime(i), Wait(i-1) * 3)
+// where Wait(-1) = BackoffTime(0). Used with UpTo or Forever, this is exactly the "decorrelated jitter" algorithm, where
+// BackoffTime is the base wait. Use a strategy with a MaxWaitBetweenAttempts to cap how large the waits can become.
+type DecorrelatedJitter struct {
+	// Source of randomness, uses math/rand if nil
+	Source RandomSource
+}
+
+func NewDecorrelatedJitter(source RandomSource) *DecorrelatedJitter {
+	return &DecorrelatedJitter{
+		Source: source,
+	}
+}
+
+func (j *DecorrelatedJitter) Apply(computed, previous time.Duration) time.Duration {
+	if previous < computed {
+		previous = computed
+	}
+	upper := time.Duration(math.MaxInt64)
+	if previous < upper/3 {
+		upper = previous * 3
+	}
+	return randomBetween(j.Source, computed, upper)
+}
@@ -0,0 +1,21 @@
+package retry
+
+import "time"
+
+// EqualJitter always waits at least half of the computed wait, then a random amount up to the other half:
+// Wait(i) = BackoffTime(i)/2 + random_between(0, BackoffTime(i)/2)
+type EqualJitter struct {
+	// Source of randomness, uses math/rand if nil
+	Source RandomSource
+}
+
+func NewEqualJitter(source RandomSource) *EqualJitter {
+	return &EqualJitter{
+		Source: source,
+	}
+}
+
+func (j *EqualJitter) Apply(computed, _ time.Duration) time.Duration {
+	half := computed / 2
+	return randomBetween(j.Source, half, computed)
+}
@@ -0,0 +1,21 @@
+package retry
+
+import "time"
+
+// FullJitter waits a random amount of time between 0 and the computed wait:
+// Wait(i) = random_between(0, BackoffTime(i))
+// This spreads out clients the most, but some retries may occur almost immediately
+type FullJitter struct {
+	// Source of randomness, uses math/rand if nil
+	Source RandomSource
+}
+
+func NewFullJitter(source RandomSource) *FullJitter {
+	return &FullJitter{
+		Source: source,
+	}
+}
+
+func (j *FullJitter) Apply(computed, _ time.Duration) time.Duration {
+	return randomBetween(j.Source, 0, computed)
+}
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"testing"
+	"time"
+)
+
+func TestJitter_Apply(t *testing.T) {
+	lowest := &retryMocks.Random{Fraction: 0}
+	highest := &retryMocks.Random{Fraction: 1}
+	cases := map[string]struct {
+		jitter   Jitter
+		computed time.Duration
+		previous time.Duration
+		expected time.Duration
+	}{
+		"full lowest": {
+			jitter:   NewFullJitter(lowest),
+			computed: 100 * timeUnit,
+			expected: 0,
+		},
+		"full highest": {
+			jitter:   NewFullJitter(highest),
+			computed: 100 * timeUnit,
+			expected: 100*timeUnit - 1,
+		},
+		"equal lowest": {
+			jitter:   NewEqualJitter(lowest),
+			computed: 100 * timeUnit,
+			expected: 50 * timeUnit,
+		},
+		"equal highest": {
+			jitter:   NewEqualJitter(highest),
+			computed: 100 * timeUnit,
+			expected: 100*timeUnit - 1,
+		},
+		"decorrelated first wait": {
+			jitter:   NewDecorrelatedJitter(highest),
+			computed: 10 * timeUnit,
+			expected: 30*timeUnit - 1,
+		},
+		"decorrelated grows from previous": {
+			jitter:   NewDecorrelatedJitter(highest),
+			computed: 10 * timeUnit,
+			previous: 30 * timeUnit,
+			expected: 90*timeUnit - 1,
+		},
+		"decorrelated lowest": {
+			jitter:   NewDecorrelatedJitter(lowest),
+			computed: 10 * timeUnit,
+			previous: 30 * timeUnit,
+			expected: 10 * timeUnit,
+		},
+		"zero wait": {
+			jitter:   NewFullJitter(highest),
+			computed: 0,
+			expected: 0,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			actual := c.jitter.Apply(c.computed, c.previous)
+			g.Expect(actual).Should(gomega.Equal(c.expected))
+		})
+	}
+}
+
+func TestJitteredWait_NextCapped(t *testing.T) {
+	g := gomega.NewWithT(t)
+	waits := jitteredWait{jitter: NewDecorrelatedJitter(&retryMocks.Random{Fraction: 1})}
+	g.Expect(waits.nextCapped(10*timeUnit, 20*timeUnit)).Should(gomega.Equal(20 * timeUnit))
+	g.Expect(waits.previous).Should(gomega.Equal(20 * timeUnit))
+}
+
+func TestJitteredWait_NoJitter(t *testing.T) {
+	g := gomega.NewWithT(t)
+	waits := jitteredWait{}
+	g.Expect(waits.next(10 * timeUnit)).Should(gomega.Equal(10 * timeUnit))
+	g.Expect(waits.nextCapped(30*timeUnit, 20*timeUnit)).Should(gomega.Equal(20 * timeUnit))
+}
+
+func TestJitteredWait_Waited(t *testing.T) {
+	g := gomega.NewWithT(t)
+	waits := jitteredWait{jitter: NewDecorrelatedJitter(&retryMocks.Random{Fraction: 1})}
+	waits.next(10 * timeUnit)
+	waits.waited(100 * timeUnit)
+	g.Expect(waits.previous).Should(gomega.Equal(100 * timeUnit))
+	// decorrelated jitter grows from the wait that really happened
+	g.Expect(waits.next(10 * timeUnit)).Should(gomega.BeNumerically(">", 200*timeUnit))
+}
@@ -3,7 +3,6 @@ package retry
 import (
 	"context"
 	"github.com/wojnosystems/go-retry/retryLoop"
-	"github.com/wojnosystems/go-retry/retrySleep"
 	"time"
 )
 
@@ -14,6 +13,12 @@ type Linear struct {
 	retryStrategy
 	InitialWaitBetweenAttempts time.Duration
 	GrowthFactor               float64
+
+	// Jitter randomizes each wait, nil means no jitter
+	Jitter Jitter
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
 }
 
 func NewLinear(initialWaitBetweenAttempts time.Duration, growthFactor float64) *Linear {
@@ -24,8 +29,66 @@ func NewLinear(initialWaitBetweenAttempts time.Duration, growthFactor float64) *
 }
 
 func (c *Linear) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
-	return retryLoop.Forever(ctx, cb, func(i uint64) {
-		sleepTime := linearSleepTime(c.InitialWaitBetweenAttempts, c.GrowthFactor, i)
-		retrySleep.WithContext(ctx, sleepTime)
-	})
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *Linear) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	return c.composed().RetryAttempt(ctx, cb)
+}
+
+// composed is the equivalent Composed strategy
+func (c *Linear) composed() *Composed {
+	return &Composed{
+		Backoff: &LinearBackoff{
+			InitialWaitBetweenAttempts: c.InitialWaitBetweenAttempts,
+			GrowthFactor:               c.GrowthFactor,
+		},
+		Jitter:  c.Jitter,
+		Options: c.Options,
+	}
+}
+
+// Schedule plans at most n waits, see Plan
+func (c *Linear) Schedule(n int) Plan {
+	return c.composed().Schedule(n)
+}
+
+// Plan plans every wait the strategy would make, see Planner
+func (c *Linear) Plan() Plan {
+	return c.composed().Plan()
+}
+
+// String is the spec of the strategy, see Parse
+func (c *Linear) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *Linear) spec() *specWriter {
+	return newSpecWriter("linear").
+		duration("initial", c.InitialWaitBetweenAttempts).
+		float("growth", c.GrowthFactor).
+		jitter(c.Jitter).
+		options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse. It fails if the strategy has a Jitter from another package
+func (c *Linear) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a Linear
+func (c *Linear) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *Linear) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *Linear) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
 }
@@ -3,7 +3,6 @@ package retry
 import (
 	"context"
 	"github.com/wojnosystems/go-retry/retryLoop"
-	"github.com/wojnosystems/go-retry/retrySleep"
 	"time"
 )
 
@@ -17,6 +16,12 @@ type LinearMaxWaitUpTo struct {
 	GrowthFactor               float64
 	MaxAttempts                uint
 	MaxWaitBetweenAttempts     time.Duration
+
+	// Jitter randomizes each wait, nil means no jitter
+	Jitter Jitter
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
 }
 
 func NewLinearMaxWaitUpTo(
@@ -34,8 +39,72 @@ func NewLinearMaxWaitUpTo(
 }
 
 func (c *LinearMaxWaitUpTo) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
-	return retryLoop.UpTo(ctx, cb, func(i uint64) {
-		sleepTime := linearSleepTime(c.InitialWaitBetweenAttempts, c.GrowthFactor, i)
-		retrySleep.WithContext(ctx, minDuration(sleepTime, c.MaxWaitBetweenAttempts))
-	}, uint64(c.MaxAttempts))
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *LinearMaxWaitUpTo) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	return c.composed().RetryAttempt(ctx, cb)
+}
+
+// composed is the equivalent Composed strategy
+func (c *LinearMaxWaitUpTo) composed() *Composed {
+	return &Composed{
+		Backoff: &CappedBackoff{
+			Backoff: &LinearBackoff{
+				InitialWaitBetweenAttempts: c.InitialWaitBetweenAttempts,
+				GrowthFactor:               c.GrowthFactor,
+			},
+			MaxWaitBetweenAttempts: c.MaxWaitBetweenAttempts,
+		},
+		Stop:    MaxAttempts(c.MaxAttempts),
+		Jitter:  c.Jitter,
+		Options: c.Options,
+	}
+}
+
+// Schedule plans at most n waits, see Plan
+func (c *LinearMaxWaitUpTo) Schedule(n int) Plan {
+	return c.composed().Schedule(n)
+}
+
+// Plan plans every wait the strategy would make, see Planner
+func (c *LinearMaxWaitUpTo) Plan() Plan {
+	return c.composed().Plan()
+}
+
+// String is the spec of the strategy, see Parse
+func (c *LinearMaxWaitUpTo) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *LinearMaxWaitUpTo) spec() *specWriter {
+	return newSpecWriter("linear").
+		duration("initial", c.InitialWaitBetweenAttempts).
+		float("growth", c.GrowthFactor).
+		uint("max_attempts", c.MaxAttempts).
+		duration("max_wait", c.MaxWaitBetweenAttempts).
+		jitter(c.Jitter).
+		options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse. It fails if the strategy has a Jitter from another package
+func (c *LinearMaxWaitUpTo) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a LinearMaxWaitUpTo
+func (c *LinearMaxWaitUpTo) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *LinearMaxWaitUpTo) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *LinearMaxWaitUpTo) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
 }
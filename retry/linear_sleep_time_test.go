@@ -2,6 +2,7 @@ package retry
 
 import (
 	"github.com/onsi/gomega"
+	"math"
 	"strconv"
 	"testing"
 	"time"
@@ -30,6 +31,12 @@ func Test_linearSleepTime(t *testing.T) {
 			iterations:   10,
 			expected:     (1 + 20) * timeUnit,
 		},
+		{
+			initial:      1 * timeUnit,
+			growthFactor: 1.0,
+			iterations:   math.MaxUint64,
+			expected:     math.MaxInt64,
+		},
 	}
 
 	for caseIndex, c := range cases {
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"time"
+)
+
+// MaxPlannedWaits is how many waits Plan lists for strategies that would retry forever
+const MaxPlannedWaits = 1000
+
+// Planner previews the waits a strategy would make. Every strategy in this package is a Planner
+type Planner interface {
+	// Schedule plans at most n waits
+	Schedule(n int) Plan
+
+	// Plan plans every wait, or the first MaxPlannedWaits if the strategy would retry forever
+	Plan() Plan
+}
+
+// Plan is what a strategy would do if every attempt failed with a retryable error and took no time.
+// Waits are planned before jitter is applied. FullJitter and EqualJitter only ever shorten them, so Total is then the
+// worst case, but DecorrelatedJitter may wait longer. Waits hinted by errors, see retryError.AgainAfter, are not known
+// ahead of time and are not planned
+type Plan struct {
+	// Attempts is how many attempts would be made, including the first
+	Attempts uint64
+
+	// Waits are the waits before each retry, in order
+	Waits []time.Duration
+
+	// Cumulative is the time waited by the end of each of the Waits
+	Cumulative []time.Duration
+
+	// Total is the sum of the Waits, which is how long the retry would take if each attempt took no time
+	Total time.Duration
+
+	// Truncated is true if the strategy would retry more than the Waits planned
+	Truncated bool
+
+	// DeadlineReached is true if the plan was cut short by Within. The last wait is cut short at the deadline and no
+	// attempt follows it, as the loop does when the context is done
+	DeadlineReached bool
+}
+
+// Within returns what the plan would do if the context had remaining time left before its deadline when the retry
+// started. Use time.Until with the context's deadline to find it
+func (p Plan) Within(remaining time.Duration) Plan {
+	if p.Attempts == 0 {
+		return p
+	}
+	cut := Plan{Attempts: 1}
+	if remaining <= 0 {
+		// the first attempt is made, but the loop stops right after it
+		cut.DeadlineReached = len(p.Waits) != 0 || p.Truncated
+		return cut
+	}
+	for _, wait := range p.Waits {
+		if cut.Total+wait >= remaining {
+			cut.addWait(remaining - cut.Total)
+			cut.DeadlineReached = true
+			return cut
+		}
+		cut.addWait(wait)
+		cut.Attempts++
+	}
+	cut.Truncated = p.Truncated
+	return cut
+}
+
+// addWait appends wait to the plan
+func (p *Plan) addWait(wait time.Duration) {
+	p.Total = saturatedSum(p.Total, wait)
+	p.Waits = append(p.Waits, wait)
+	p.Cumulative = append(p.Cumulative, p.Total)
+}
+
+// Schedule plans at most n waits, consulting Stop as the strategy would, see Plan
+func (c *Composed) Schedule(n int) Plan {
+	plan := Plan{Attempts: 1}
+	waits := jitteredWait{}
+	progress := Progress{}
+	for {
+		progress.Attempts = plan.Attempts
+		progress.Elapsed = progress.TotalWait
+		progress.NextWait = waits.delay(c.Backoff, plan.Attempts-1)
+		if c.Stop != nil && c.Stop.ShouldStop(progress) {
+			return plan
+		}
+		if len(plan.Waits) >= n {
+			plan.Truncated = true
+			return plan
+		}
+		progress.TotalWait = saturatedSum(progress.TotalWait, progress.NextWait)
+		plan.addWait(progress.NextWait)
+		plan.Attempts++
+	}
+}
+
+// Plan plans every wait, see Planner
+func (c *Composed) Plan() Plan {
+	return c.Schedule(MaxPlannedWaits)
+}
@@ -0,0 +1,115 @@
+package retry_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"math"
+	"time"
+)
+
+var _ = Describe("Plan", func() {
+	ms := func(values ...int) (durations []time.Duration) {
+		for _, v := range values {
+			durations = append(durations, time.Duration(v)*time.Millisecond)
+		}
+		return
+	}
+
+	It("lists the waits of a capped exponential back off", func() {
+		plan := retry.NewExponentialMaxWaitUpTo(50*time.Millisecond, 1.0, 15, 500*time.Millisecond).Plan()
+		Expect(plan.Attempts).Should(Equal(uint64(15)))
+		Expect(plan.Waits).Should(Equal(ms(50, 100, 200, 400, 500, 500, 500, 500, 500, 500, 500, 500, 500, 500)))
+		Expect(plan.Cumulative[:5]).Should(Equal(ms(50, 150, 350, 750, 1250)))
+		Expect(plan.Total).Should(Equal(5750 * time.Millisecond))
+		Expect(plan.Cumulative[len(plan.Cumulative)-1]).Should(Equal(plan.Total))
+		Expect(plan.Truncated).Should(BeFalse())
+		Expect(plan.DeadlineReached).Should(BeFalse())
+	})
+
+	It("never plans negative waits for long exponential back offs", func() {
+		plan := retry.NewExponential(50*time.Millisecond, 1.0).Schedule(60)
+		Expect(plan.Waits).Should(HaveLen(60))
+		Expect(plan.Waits[59]).Should(Equal(time.Duration(math.MaxInt64)))
+		for i := 1; i < len(plan.Waits); i++ {
+			Expect(plan.Waits[i]).Should(BeNumerically(">=", plan.Waits[i-1]))
+		}
+		Expect(plan.Total).Should(Equal(time.Duration(math.MaxInt64)))
+	})
+
+	It("keeps capping long exponential back offs", func() {
+		plan := retry.NewExponentialMaxWaitUpTo(50*time.Millisecond, 1.0, 100, time.Second).Plan()
+		Expect(plan.Waits).Should(HaveLen(99))
+		Expect(plan.Waits[98]).Should(Equal(time.Second))
+		Expect(plan.Total).Should(Equal(50*time.Millisecond + 100*time.Millisecond + 200*time.Millisecond +
+			400*time.Millisecond + 800*time.Millisecond + 94*time.Second))
+	})
+
+	DescribeTable("plans the waits of each strategy",
+		func(strategy retry.Planner, attempts uint64, waits []time.Duration) {
+			plan := strategy.Plan()
+			Expect(plan.Attempts).Should(Equal(attempts))
+			Expect(plan.Waits).Should(Equal(waits))
+		},
+		Entry("skip", retry.Skip, uint64(0), nil),
+		Entry("never", retry.Never, uint64(1), nil),
+		Entry("up to", retry.NewUpTo(10*time.Millisecond, 3), uint64(3), ms(10, 10)),
+		Entry("linear up to", retry.NewLinearUpTo(10*time.Millisecond, 1, 4), uint64(4), ms(10, 20, 30)),
+		Entry("linear max wait up to", retry.NewLinearMaxWaitUpTo(10*time.Millisecond, 1, 5, 25*time.Millisecond), uint64(5), ms(10, 20, 25, 25)),
+		Entry("exponential up to", retry.NewExponentialUpTo(10*time.Millisecond, 2, 4), uint64(4), ms(10, 30, 90)),
+		Entry("hedged", retry.NewHedged(20*time.Millisecond, 3, 0), uint64(3), ms(20, 20)),
+		Entry("hedged with no attempts set", retry.NewHedged(20*time.Millisecond, 0, 0), uint64(1), nil),
+		Entry("composed", retry.NewComposed(retry.NewConstantBackoff(10*time.Millisecond), retry.MaxTotalWait(35*time.Millisecond)), uint64(4), ms(10, 10, 10)),
+	)
+
+	When("the strategy retries forever", func() {
+		It("truncates the schedule", func() {
+			plan := retry.NewForever(time.Second).Schedule(3)
+			Expect(plan.Waits).Should(Equal([]time.Duration{time.Second, time.Second, time.Second}))
+			Expect(plan.Attempts).Should(Equal(uint64(4)))
+			Expect(plan.Truncated).Should(BeTrue())
+		})
+
+		It("plans up to MaxPlannedWaits", func() {
+			plan := retry.NewExponential(time.Millisecond, 0).Plan()
+			Expect(plan.Waits).Should(HaveLen(retry.MaxPlannedWaits))
+			Expect(plan.Truncated).Should(BeTrue())
+		})
+	})
+
+	It("does not truncate a schedule that ends at n", func() {
+		plan := retry.NewUpTo(time.Second, 3).Schedule(2)
+		Expect(plan.Waits).Should(HaveLen(2))
+		Expect(plan.Truncated).Should(BeFalse())
+	})
+
+	Describe("Within", func() {
+		plan := retry.NewLinearUpTo(100*time.Millisecond, 1, 5).Plan()
+
+		It("cuts the schedule at the deadline", func() {
+			cut := plan.Within(400 * time.Millisecond)
+			Expect(cut.Waits).Should(Equal(ms(100, 200, 100)))
+			Expect(cut.Cumulative).Should(Equal(ms(100, 300, 400)))
+			Expect(cut.Total).Should(Equal(400 * time.Millisecond))
+			Expect(cut.Attempts).Should(Equal(uint64(3)))
+			Expect(cut.DeadlineReached).Should(BeTrue())
+		})
+
+		It("keeps the schedule if it ends before the deadline", func() {
+			cut := plan.Within(time.Minute)
+			Expect(cut).Should(Equal(plan))
+		})
+
+		It("makes only the first attempt if the deadline has passed", func() {
+			cut := plan.Within(0)
+			Expect(cut.Attempts).Should(Equal(uint64(1)))
+			Expect(cut.Waits).Should(BeEmpty())
+			Expect(cut.DeadlineReached).Should(BeTrue())
+		})
+
+		It("makes no attempts for Skip", func() {
+			Expect(retry.Skip.Plan().Within(time.Second).Attempts).Should(BeZero())
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// saturatedDuration converts nanoseconds to a Duration, using the longest Duration when it is too long for one
+func saturatedDuration(nanoseconds float64) time.Duration {
+	if nanoseconds >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(nanoseconds)
+}
+
+// saturatedSum adds b to a, using the longest Duration when the sum is too long for one. b must not be negative
+func saturatedSum(a, b time.Duration) time.Duration {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
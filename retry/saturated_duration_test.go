@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"github.com/onsi/gomega"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSaturatedDuration(t *testing.T) {
+	cases := map[string]struct {
+		nanoseconds float64
+		expected    time.Duration
+	}{
+		"fits": {
+			nanoseconds: float64(10 * time.Millisecond),
+			expected:    10 * time.Millisecond,
+		},
+		"too long": {
+			nanoseconds: math.Pow(2, 70),
+			expected:    math.MaxInt64,
+		},
+		"infinite": {
+			nanoseconds: math.Inf(1),
+			expected:    math.MaxInt64,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			actual := saturatedDuration(c.nanoseconds)
+			g.Expect(actual).Should(gomega.Equal(c.expected))
+		})
+	}
+}
+
+func TestSaturatedSum(t *testing.T) {
+	cases := map[string]struct {
+		a, b     time.Duration
+		expected time.Duration
+	}{
+		"fits": {
+			a:        10 * time.Millisecond,
+			b:        20 * time.Millisecond,
+			expected: 30 * time.Millisecond,
+		},
+		"too long": {
+			a:        math.MaxInt64 - 1,
+			b:        2,
+			expected: math.MaxInt64,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			actual := saturatedSum(c.a, c.b)
+			g.Expect(actual).Should(gomega.Equal(c.expected))
+		})
+	}
+}
@@ -17,3 +17,32 @@ type skip struct {
 func (s *skip) Retry(_ context.Context, _ retryLoop.CallbackFunc) (err error) {
 	return retryError.StopSuccess
 }
+
+func (s *skip) RetryAttempt(_ context.Context, _ retryLoop.AttemptCallbackFunc) (err error) {
+	return retryError.StopSuccess
+}
+
+// Schedule is empty, as Skip makes no attempts
+func (s *skip) Schedule(_ int) Plan {
+	return Plan{}
+}
+
+// Plan is empty, as Skip makes no attempts
+func (s *skip) Plan() Plan {
+	return Plan{}
+}
+
+// String is the spec of Skip, see Parse
+func (s *skip) String() string {
+	return "skip"
+}
+
+// MarshalText writes the spec of Skip, see Parse
+func (s *skip) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// MarshalJSON writes the spec of Skip as a JSON string
+func (s *skip) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(s)
+}
@@ -0,0 +1,313 @@
+package retry
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrUnknownStrategy is matched by errors.Is when a spec names a strategy that does not exist
+	ErrUnknownStrategy = errors.New("unknown strategy")
+
+	// ErrUnknownParameter is matched by errors.Is when a spec has a parameter the strategy does not take
+	ErrUnknownParameter = errors.New("unknown parameter")
+
+	// ErrMissingParameter is matched by errors.Is when a spec is missing a parameter the strategy needs
+	ErrMissingParameter = errors.New("missing parameter")
+
+	// ErrInvalidValue is matched by errors.Is when a spec's parameter can't be read or is out of range
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrWrongStrategy is matched by errors.Is when a spec is unmarshalled into a different type of strategy
+	ErrWrongStrategy = errors.New("wrong strategy")
+
+	// ErrNotSerializable is matched by errors.Is when a strategy, or a part of it, can't be written as a spec
+	ErrNotSerializable = errors.New("strategy cannot be written as a spec")
+)
+
+// SpecError is returned when a spec can't be parsed. Use errors.Is to check which of the Err values above caused it
+type SpecError struct {
+	// Spec is the spec that could not be parsed
+	Spec string
+
+	// Err is why
+	Err error
+}
+
+func (e *SpecError) Error() string {
+	return fmt.Sprintf("invalid retry spec %q: %s", e.Spec, e.Err)
+}
+
+// Unwrap returns why the spec could not be parsed
+func (e *SpecError) Unwrap() error {
+	return e.Err
+}
+
+// Parse creates a strategy from a spec such as "exponential(initial=50ms,growth=1.0,max_attempts=15,max_wait=500ms)".
+// A spec is the name of a strategy, followed by its parameters in parentheses, if it takes any. Durations are
+// written as for time.ParseDuration. The strategies are:
+//   - skip: Skip
+//   - never: Never
+//   - constant(wait): Forever, or UpTo if max_attempts is given
+//   - linear(initial, growth): Linear, LinearUpTo if max_attempts is given, and LinearMaxWaitUpTo if max_wait is too
+//   - exponential(initial, growth): the same as linear, for Exponential, ExponentialUpTo and ExponentialMaxWaitUpTo
+//   - hedged(delay, max_attempts): Hedged, optionally with max_in_flight
+//   - composed: Composed, with backoff=constant(wait), linear(initial, growth) or exponential(initial, growth),
+//     optionally capped by max_wait, and stopping at any of max_attempts, max_elapsed and max_total_wait, as made by
+//     a Builder. Without a backoff it does not wait, and without a stop policy it never gives up
+//
+// Every strategy but skip and never may also have attempt_timeout, max_retry_after, keep_history and deadline_aware,
+// see retryLoop.Options, and
+// every strategy but hedged may have jitter, which is full, equal or decorrelated.
+// The String method of each strategy returns its spec, so Parse(strategy.String()) is the same strategy, except for
+// fields that can't be written down, such as the Observer. A Jitter from another package is written as "custom",
+// which Parse rejects, so MarshalText fails with ErrNotSerializable instead
+func Parse(spec string) (strategy Strategy, err error) {
+	name, params, err := splitSpec(spec)
+	if err != nil {
+		return nil, &SpecError{Spec: spec, Err: err}
+	}
+	p := &specParams{values: params}
+	switch name {
+	case "skip":
+		strategy = Skip
+	case "never":
+		strategy = &UpTo{}
+	case "constant":
+		strategy = parseConstant(p)
+	case "linear":
+		strategy = parseLinear(p)
+	case "exponential":
+		strategy = parseExponential(p)
+	case "hedged":
+		strategy = parseHedged(p)
+	case "composed":
+		strategy = parseComposed(p)
+	default:
+		return nil, &SpecError{Spec: spec, Err: fmt.Errorf("%w %q", ErrUnknownStrategy, name)}
+	}
+	if err = p.finish(); err != nil {
+		return nil, &SpecError{Spec: spec, Err: err}
+	}
+	return strategy, nil
+}
+
+// MustParse is like Parse, but panics if the spec is invalid. Use it for specs that are constants in your code
+func MustParse(spec string) Strategy {
+	strategy, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return strategy
+}
+
+// ParseEnv parses the spec in the environment variable named key. If it is not set or is empty, fallback is returned
+func ParseEnv(key string, fallback Strategy) (Strategy, error) {
+	spec := strings.TrimSpace(os.Getenv(key))
+	if spec == "" {
+		return fallback, nil
+	}
+	strategy, err := Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return strategy, nil
+}
+
+// Spec holds a Strategy so that it can be read from and written to text, JSON and environment variables, such as in
+// a configuration struct, without knowing ahead of time which strategy it is. See Parse for the format.
+// Strategies from other packages, and Composed strategies with parts from other packages or a max wait that caps
+// another max wait, cannot be written as specs
+type Spec struct {
+	Strategy
+}
+
+// String is the spec of the strategy
+func (s Spec) String() string {
+	if stringer, ok := s.Strategy.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", s.Strategy)
+}
+
+// MarshalText writes the spec of the strategy
+func (s Spec) MarshalText() ([]byte, error) {
+	marshaler, ok := s.Strategy.(encoding.TextMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotSerializable, s.Strategy)
+	}
+	return marshaler.MarshalText()
+}
+
+// UnmarshalText parses the spec into a new strategy
+func (s *Spec) UnmarshalText(text []byte) (err error) {
+	s.Strategy, err = Parse(string(text))
+	return
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (s Spec) MarshalJSON() ([]byte, error) {
+	text, err := s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into a new strategy
+func (s *Spec) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return s.UnmarshalText([]byte(text))
+}
+
+// unmarshalSpec parses text into target, which must be the type of strategy the spec describes
+func unmarshalSpec[T any](text []byte, target *T) error {
+	strategy, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	parsed, ok := any(strategy).(*T)
+	if !ok {
+		return &SpecError{Spec: string(text), Err: fmt.Errorf("%w: expected %T, got %T", ErrWrongStrategy, target, strategy)}
+	}
+	*target = *parsed
+	return nil
+}
+
+// unmarshalJSONSpec parses a JSON string containing a spec into target, see unmarshalSpec
+func unmarshalJSONSpec[T any](data []byte, target *T) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return unmarshalSpec([]byte(text), target)
+}
+
+// marshalJSONSpec writes the spec as a JSON string
+func marshalJSONSpec(spec encoding.TextMarshaler) ([]byte, error) {
+	text, err := spec.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func parseConstant(p *specParams) Strategy {
+	wait := p.duration("wait")
+	jitter := p.jitter()
+	options := p.options()
+	if !p.has("max_attempts") {
+		return &Forever{WaitBetweenAttempts: wait, Jitter: jitter, Options: options}
+	}
+	return &UpTo{WaitBetweenAttempts: wait, MaxAttempts: p.uint("max_attempts"), Jitter: jitter, Options: options}
+}
+
+func parseLinear(p *specParams) Strategy {
+	initial := p.duration("initial")
+	growth := p.growth()
+	jitter := p.jitter()
+	options := p.options()
+	switch {
+	case p.has("max_wait"):
+		return &LinearMaxWaitUpTo{
+			InitialWaitBetweenAttempts: initial,
+			GrowthFactor:               growth,
+			MaxAttempts:                p.uint("max_attempts"),
+			MaxWaitBetweenAttempts:     p.duration("max_wait"),
+			Jitter:                     jitter,
+			Options:                    options,
+		}
+	case p.has("max_attempts"):
+		return &LinearUpTo{
+			InitialWaitBetweenAttempts: initial,
+			GrowthFactor:               growth,
+			MaxAttempts:                p.uint("max_attempts"),
+			Jitter:                     jitter,
+			Options:                    options,
+		}
+	default:
+		return &Linear{InitialWaitBetweenAttempts: initial, GrowthFactor: growth, Jitter: jitter, Options: options}
+	}
+}
+
+func parseExponential(p *specParams) Strategy {
+	initial := p.duration("initial")
+	growth := p.growth()
+	jitter := p.jitter()
+	options := p.options()
+	switch {
+	case p.has("max_wait"):
+		return &ExponentialMaxWaitUpTo{
+			InitialWaitBetweenAttempts: initial,
+			GrowthFactor:               growth,
+			MaxAttempts:                p.uint("max_attempts"),
+			MaxWaitBetweenAttempts:     p.duration("max_wait"),
+			Jitter:                     jitter,
+			Options:                    options,
+		}
+	case p.has("max_attempts"):
+		return &ExponentialUpTo{
+			InitialWaitBetweenAttempts: initial,
+			GrowthFactor:               growth,
+			MaxAttempts:                p.uint("max_attempts"),
+			Jitter:                     jitter,
+			Options:                    options,
+		}
+	default:
+		return &Exponential{InitialWaitBetweenAttempts: initial, GrowthFactor: growth, Jitter: jitter, Options: options}
+	}
+}
+
+func parseHedged(p *specParams) Strategy {
+	hedged := &Hedged{
+		Delay:       p.duration("delay"),
+		MaxAttempts: p.uint("max_attempts"),
+		Options:     p.options(),
+	}
+	if p.has("max_in_flight") {
+		hedged.MaxInFlight = p.uint("max_in_flight")
+	}
+	return hedged
+}
+
+func parseComposed(p *specParams) Strategy {
+	var backoff Backoff
+	if p.has("backoff") {
+		kind, _ := p.value("backoff")
+		switch kind {
+		case "constant":
+			backoff = NewConstantBackoff(p.duration("wait"))
+		case "linear":
+			backoff = NewLinearBackoff(p.duration("initial"), p.growth())
+		case "exponential":
+			backoff = NewExponentialBackoff(p.duration("initial"), p.growth())
+		default:
+			p.invalid("backoff", kind, "is not constant, linear or exponential")
+		}
+	}
+	builder := NewBuilder(backoff)
+	if p.has("max_wait") {
+		if backoff == nil {
+			// there is nothing to cap, so the backoff must be missing or invalid
+			p.value("backoff")
+		}
+		builder.MaxWait(p.duration("max_wait"))
+	}
+	if p.has("max_attempts") {
+		builder.StopWhen(MaxAttempts(p.uint("max_attempts")))
+	}
+	if p.has("max_elapsed") {
+		builder.StopWhen(MaxElapsed(p.duration("max_elapsed")))
+	}
+	if p.has("max_total_wait") {
+		builder.StopWhen(MaxTotalWait(p.duration("max_total_wait")))
+	}
+	return builder.Jitter(p.jitter()).Options(p.options()).Build()
+}
@@ -0,0 +1,338 @@
+package retry
+
+import (
+	"fmt"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// splitSpec splits "name(key=value,...)" into its name and parameters
+func splitSpec(spec string) (name string, params map[string]string, err error) {
+	spec = strings.TrimSpace(spec)
+	params = make(map[string]string)
+	open := strings.IndexByte(spec, '(')
+	if open < 0 {
+		return spec, params, nil
+	}
+	if !strings.HasSuffix(spec, ")") {
+		return "", nil, fmt.Errorf("%w: missing closing parenthesis", ErrInvalidValue)
+	}
+	name = strings.TrimSpace(spec[:open])
+	body := strings.TrimSpace(spec[open+1 : len(spec)-1])
+	if body == "" {
+		return name, params, nil
+	}
+	for _, pair := range strings.Split(body, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return "", nil, fmt.Errorf("%w: parameter %q is not key=value", ErrInvalidValue, strings.TrimSpace(pair))
+		}
+		if _, duplicate := params[key]; duplicate {
+			return "", nil, fmt.Errorf("%w: parameter %q is given more than once", ErrInvalidValue, key)
+		}
+		params[key] = value
+	}
+	return name, params, nil
+}
+
+// specParams reads the parameters of a spec. The first problem is kept in err, and reported by finish along with any
+// parameters that were never read
+type specParams struct {
+	values map[string]string
+	read   map[string]bool
+	err    error
+}
+
+func (p *specParams) has(key string) bool {
+	_, ok := p.values[key]
+	return ok
+}
+
+// value returns the parameter, recording that it is missing if it is not there
+func (p *specParams) value(key string) (string, bool) {
+	if p.read == nil {
+		p.read = make(map[string]bool)
+	}
+	p.read[key] = true
+	value, ok := p.values[key]
+	if !ok {
+		p.fail(fmt.Errorf("%w %q", ErrMissingParameter, key))
+	}
+	return value, ok
+}
+
+func (p *specParams) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *specParams) invalid(key, value, why string) {
+	p.fail(fmt.Errorf("%w for %q: %q %s", ErrInvalidValue, key, value, why))
+}
+
+func (p *specParams) duration(key string) time.Duration {
+	value, ok := p.value(key)
+	if !ok {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		p.invalid(key, value, "is not a duration")
+		return 0
+	}
+	if d < 0 {
+		p.invalid(key, value, "is negative")
+		return 0
+	}
+	return d
+}
+
+func (p *specParams) uint(key string) uint {
+	value, ok := p.value(key)
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+	if err != nil {
+		p.invalid(key, value, "is not a whole number")
+		return 0
+	}
+	return uint(n)
+}
+
+func (p *specParams) bool(key string) bool {
+	value, ok := p.value(key)
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		p.invalid(key, value, "is not true or false")
+		return false
+	}
+	return b
+}
+
+// growth reads the GrowthFactor, which must be a finite number that is not negative
+func (p *specParams) growth() float64 {
+	value, ok := p.value("growth")
+	if !ok {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.invalid("growth", value, "is not a number")
+		return 0
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		p.invalid("growth", value, "is not finite")
+		return 0
+	}
+	if f < 0 {
+		p.invalid("growth", value, "is negative")
+		return 0
+	}
+	return f
+}
+
+// jitter reads the optional jitter mode
+func (p *specParams) jitter() Jitter {
+	if !p.has("jitter") {
+		return nil
+	}
+	value, _ := p.value("jitter")
+	switch value {
+	case "full":
+		return &FullJitter{}
+	case "equal":
+		return &EqualJitter{}
+	case "decorrelated":
+		return &DecorrelatedJitter{}
+	}
+	p.invalid("jitter", value, "is not full, equal or decorrelated")
+	return nil
+}
+
+// options reads the optional retryLoop.Options that can be written down
+func (p *specParams) options() (options retryLoop.Options) {
+	if p.has("attempt_timeout") {
+		options.PerAttemptTimeout = p.duration("attempt_timeout")
+	}
+	if p.has("max_retry_after") {
+		options.MaxRetryAfter = p.duration("max_retry_after")
+	}
+	if p.has("keep_history") {
+		options.KeepHistory = p.bool("keep_history")
+	}
+	if p.has("deadline_aware") {
+		options.DeadlineAware = p.bool("deadline_aware")
+	}
+	return
+}
+
+// finish returns the first problem found, or that a parameter was given that the strategy does not take
+func (p *specParams) finish() error {
+	if p.err != nil {
+		return p.err
+	}
+	var unknown []string
+	for key := range p.values {
+		if !p.read[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%w %q", ErrUnknownParameter, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// specWriter writes the spec of a strategy, the reverse of specParams
+type specWriter struct {
+	name   string
+	params []string
+	err    error
+}
+
+func newSpecWriter(name string) *specWriter {
+	return &specWriter{name: name}
+}
+
+func (w *specWriter) duration(key string, d time.Duration) *specWriter {
+	w.params = append(w.params, key+"="+d.String())
+	return w
+}
+
+func (w *specWriter) uint(key string, n uint) *specWriter {
+	w.params = append(w.params, key+"="+strconv.FormatUint(uint64(n), 10))
+	return w
+}
+
+func (w *specWriter) float(key string, f float64) *specWriter {
+	w.params = append(w.params, key+"="+strconv.FormatFloat(f, 'f', -1, 64))
+	return w
+}
+
+// jitter writes the jitter mode, if any. Jitters from other packages are written as "custom", which Parse rejects,
+// so MarshalText fails
+func (w *specWriter) jitter(jitter Jitter) *specWriter {
+	if jitter == nil {
+		return w
+	}
+	var mode string
+	switch jitter.(type) {
+	case *FullJitter:
+		mode = "full"
+	case *EqualJitter:
+		mode = "equal"
+	case *DecorrelatedJitter:
+		mode = "decorrelated"
+	default:
+		mode = "custom"
+		w.unserializable(jitter)
+	}
+	w.params = append(w.params, "jitter="+mode)
+	return w
+}
+
+// backoff writes the backoff and its parameters, if there is one. A CappedBackoff is written as max_wait, so it must
+// cap one of the other backoffs
+func (w *specWriter) backoff(backoff Backoff) *specWriter {
+	if capped, ok := backoff.(*CappedBackoff); ok {
+		switch capped.Backoff.(type) {
+		case nil, *CappedBackoff:
+			w.unserializable(capped)
+		}
+		w.backoff(capped.Backoff)
+		return w.duration("max_wait", capped.MaxWaitBetweenAttempts)
+	}
+	switch b := backoff.(type) {
+	case nil:
+	case *ConstantBackoff:
+		w.params = append(w.params, "backoff=constant")
+		w.duration("wait", b.WaitBetweenAttempts)
+	case *LinearBackoff:
+		w.params = append(w.params, "backoff=linear")
+		w.duration("initial", b.InitialWaitBetweenAttempts).float("growth", b.GrowthFactor)
+	case *ExponentialBackoff:
+		w.params = append(w.params, "backoff=exponential")
+		w.duration("initial", b.InitialWaitBetweenAttempts).float("growth", b.GrowthFactor)
+	default:
+		w.params = append(w.params, "backoff=custom")
+		w.unserializable(backoff)
+	}
+	return w
+}
+
+// stop writes the stop policies, if any. The policies of an AnyOf are written one by one, so each type of policy
+// may only be used once
+func (w *specWriter) stop(stop StopPolicy) *specWriter {
+	policies, ok := stop.(AnyOf)
+	if !ok && stop != nil {
+		policies = AnyOf{stop}
+	}
+	written := make(map[string]bool)
+	for _, policy := range policies {
+		key, value := "stop", "custom"
+		switch p := policy.(type) {
+		case MaxAttempts:
+			key, value = "max_attempts", strconv.FormatUint(uint64(p), 10)
+		case MaxElapsed:
+			key, value = "max_elapsed", time.Duration(p).String()
+		case MaxTotalWait:
+			key, value = "max_total_wait", time.Duration(p).String()
+		}
+		if key == "stop" || written[key] {
+			w.unserializable(policy)
+		}
+		written[key] = true
+		w.params = append(w.params, key+"="+value)
+	}
+	return w
+}
+
+// unserializable records that part, such as a Jitter, can't be written in a way that Parse reads back
+func (w *specWriter) unserializable(part any) {
+	if w.err == nil {
+		w.err = fmt.Errorf("%w: %T", ErrNotSerializable, part)
+	}
+}
+
+// options writes the retryLoop.Options that are set and can be written down
+func (w *specWriter) options(options retryLoop.Options) *specWriter {
+	if options.PerAttemptTimeout != 0 {
+		w.duration("attempt_timeout", options.PerAttemptTimeout)
+	}
+	if options.MaxRetryAfter != 0 {
+		w.duration("max_retry_after", options.MaxRetryAfter)
+	}
+	if options.KeepHistory {
+		w.params = append(w.params, "keep_history=true")
+	}
+	if options.DeadlineAware {
+		w.params = append(w.params, "deadline_aware=true")
+	}
+	return w
+}
+
+// MarshalText writes the spec, or fails if part of it can't be parsed back
+func (w *specWriter) MarshalText() ([]byte, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	return []byte(w.String()), nil
+}
+
+func (w *specWriter) String() string {
+	if len(w.params) == 0 {
+		return w.name
+	}
+	return w.name + "(" + strings.Join(w.params, ",") + ")"
+}
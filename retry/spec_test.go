@@ -0,0 +1,247 @@
+package retry_test
+
+import (
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"os"
+	"time"
+)
+
+var _ = Describe("Parse", func() {
+	DescribeTable("creates the strategy the spec describes",
+		func(spec string, expected retry.Strategy) {
+			actual, err := retry.Parse(spec)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(actual).Should(Equal(expected))
+		},
+		Entry("skip", "skip", retry.Skip),
+		Entry("never", "never", &retry.UpTo{}),
+		Entry("constant", "constant(wait=1s)", &retry.Forever{WaitBetweenAttempts: time.Second}),
+		Entry("constant with max_attempts", "constant(wait=1s,max_attempts=3)", retry.NewUpTo(time.Second, 3)),
+		Entry("linear", "linear(initial=10ms,growth=2)", retry.NewLinear(10*time.Millisecond, 2)),
+		Entry("linear with max_attempts", "linear(initial=10ms,growth=2,max_attempts=4)", retry.NewLinearUpTo(10*time.Millisecond, 2, 4)),
+		Entry("linear with max_wait", "linear(initial=10ms,growth=2,max_attempts=4,max_wait=1s)", retry.NewLinearMaxWaitUpTo(10*time.Millisecond, 2, 4, time.Second)),
+		Entry("exponential", "exponential(initial=50ms,growth=1.5)", retry.NewExponential(50*time.Millisecond, 1.5)),
+		Entry("exponential with max_attempts", "exponential(initial=50ms,growth=1.5,max_attempts=15)", retry.NewExponentialUpTo(50*time.Millisecond, 1.5, 15)),
+		Entry("exponential with max_wait", "exponential(initial=50ms,growth=1.0,max_attempts=15,max_wait=500ms)", retry.NewExponentialMaxWaitUpTo(50*time.Millisecond, 1.0, 15, 500*time.Millisecond)),
+		Entry("hedged", "hedged(delay=20ms,max_attempts=3)", retry.NewHedged(20*time.Millisecond, 3, 0)),
+		Entry("hedged with max_in_flight", "hedged(delay=20ms,max_attempts=3,max_in_flight=2)", retry.NewHedged(20*time.Millisecond, 3, 2)),
+		Entry("spaces", " constant( wait = 1s , max_attempts = 3 ) ", retry.NewUpTo(time.Second, 3)),
+		Entry("jitter", "constant(wait=1s,jitter=full)", &retry.Forever{WaitBetweenAttempts: time.Second, Jitter: &retry.FullJitter{}}),
+		Entry("options", "constant(wait=1s,attempt_timeout=2s,max_retry_after=3s)", &retry.Forever{
+			WaitBetweenAttempts: time.Second,
+			Options:             retryLoop.Options{PerAttemptTimeout: 2 * time.Second, MaxRetryAfter: 3 * time.Second},
+		}),
+		Entry("keep_history", "constant(wait=1s,keep_history=true)", &retry.Forever{
+			WaitBetweenAttempts: time.Second,
+			Options:             retryLoop.Options{KeepHistory: true},
+		}),
+		Entry("composed", "composed(backoff=linear,initial=10ms,growth=1,max_wait=1s,max_elapsed=1m)",
+			retry.NewBuilder(retry.NewLinearBackoff(10*time.Millisecond, 1)).MaxWait(time.Second).StopWhen(retry.MaxElapsed(time.Minute)).Build()),
+		Entry("composed without a backoff or stop", "composed", retry.NewBuilder(nil).Build()),
+	)
+
+	DescribeTable("rejects invalid specs",
+		func(spec string, expected error) {
+			_, err := retry.Parse(spec)
+			Expect(err).Should(MatchError(expected))
+			var specErr *retry.SpecError
+			Expect(err).Should(BeAssignableToTypeOf(specErr))
+		},
+		Entry("unknown strategy", "fibonacci(initial=1s)", retry.ErrUnknownStrategy),
+		Entry("unknown parameter", "constant(wait=1s,tries=3)", retry.ErrUnknownParameter),
+		Entry("parameter on skip", "skip(wait=1s)", retry.ErrUnknownParameter),
+		Entry("jitter on hedged", "hedged(delay=1s,max_attempts=2,jitter=full)", retry.ErrUnknownParameter),
+		Entry("missing parameter", "linear(initial=1s)", retry.ErrMissingParameter),
+		Entry("max_wait without max_attempts", "exponential(initial=1s,growth=2,max_wait=5s)", retry.ErrMissingParameter),
+		Entry("bad duration", "constant(wait=soon)", retry.ErrInvalidValue),
+		Entry("negative duration", "constant(wait=-1s)", retry.ErrInvalidValue),
+		Entry("negative growth", "linear(initial=1s,growth=-2)", retry.ErrInvalidValue),
+		Entry("NaN growth", "linear(initial=1s,growth=NaN)", retry.ErrInvalidValue),
+		Entry("infinite growth", "exponential(initial=1s,growth=+Inf)", retry.ErrInvalidValue),
+		Entry("bad number", "constant(wait=1s,max_attempts=many)", retry.ErrInvalidValue),
+		Entry("bad bool", "constant(wait=1s,deadline_aware=maybe)", retry.ErrInvalidValue),
+		Entry("unknown jitter", "constant(wait=1s,jitter=some)", retry.ErrInvalidValue),
+		Entry("not key=value", "constant(1s)", retry.ErrInvalidValue),
+		Entry("duplicate parameter", "constant(wait=1s,wait=2s)", retry.ErrInvalidValue),
+		Entry("unclosed", "constant(wait=1s", retry.ErrInvalidValue),
+		Entry("unknown backoff", "composed(backoff=fibonacci)", retry.ErrInvalidValue),
+		Entry("max_wait without backoff", "composed(max_wait=1s)", retry.ErrMissingParameter),
+		Entry("backoff parameter missing", "composed(backoff=exponential,initial=1s)", retry.ErrMissingParameter),
+	)
+
+	It("panics in MustParse if the spec is invalid", func() {
+		Expect(func() { retry.MustParse("constant") }).Should(Panic())
+	})
+})
+
+var _ = Describe("String", func() {
+	DescribeTable("round-trips through Parse",
+		func(strategy retry.Strategy) {
+			spec := strategy.(interface{ String() string }).String()
+			parsed, err := retry.Parse(spec)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed).Should(Equal(strategy))
+		},
+		Entry("skip", retry.Skip),
+		Entry("never", retry.Never),
+		Entry("up to", retry.NewUpTo(time.Second, 3)),
+		Entry("forever", retry.NewForever(time.Second)),
+		Entry("linear", retry.NewLinear(10*time.Millisecond, 2)),
+		Entry("linear up to", retry.NewLinearUpTo(10*time.Millisecond, 2.5, 4)),
+		Entry("linear max wait up to", retry.NewLinearMaxWaitUpTo(10*time.Millisecond, 2, 4, time.Second)),
+		Entry("exponential", retry.NewExponential(time.Millisecond, 1.1)),
+		Entry("exponential up to", retry.NewExponentialUpTo(time.Millisecond, 2, 5)),
+		Entry("exponential max wait up to", retry.NewExponentialMaxWaitUpTo(50*time.Millisecond, 1.0, 15, 500*time.Millisecond)),
+		Entry("hedged", retry.NewHedged(20*time.Millisecond, 3, 2)),
+		Entry("composed", retry.NewBuilder(retry.NewExponentialBackoff(time.Millisecond, 2)).
+			MaxWait(time.Second).
+			StopWhen(retry.MaxAttempts(5), retry.MaxElapsed(time.Minute), retry.MaxTotalWait(30*time.Second)).
+			Jitter(&retry.FullJitter{}).
+			Options(retryLoop.Options{PerAttemptTimeout: time.Second}).
+			Build()),
+		Entry("composed constant", retry.NewBuilder(retry.NewConstantBackoff(time.Second)).StopWhen(retry.MaxAttempts(3)).Build()),
+		Entry("jitter and options", &retry.ExponentialUpTo{
+			InitialWaitBetweenAttempts: time.Millisecond,
+			GrowthFactor:               2,
+			MaxAttempts:                5,
+			Jitter:                     &retry.DecorrelatedJitter{},
+			Options:                    retryLoop.Options{PerAttemptTimeout: time.Second, MaxRetryAfter: time.Minute, KeepHistory: true, DeadlineAware: true},
+		}),
+	)
+
+	It("writes the spec", func() {
+		strategy := retry.NewExponentialMaxWaitUpTo(50*time.Millisecond, 1.5, 15, 500*time.Millisecond)
+		Expect(strategy.String()).Should(Equal("exponential(initial=50ms,growth=1.5,max_attempts=15,max_wait=500ms)"))
+	})
+})
+
+var _ = Describe("JSON", func() {
+	It("round-trips a concrete strategy", func() {
+		data, err := json.Marshal(retry.NewLinearUpTo(time.Second, 2, 3))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).Should(Equal(`"linear(initial=1s,growth=2,max_attempts=3)"`))
+		var actual retry.LinearUpTo
+		Expect(json.Unmarshal(data, &actual)).Should(Succeed())
+		Expect(&actual).Should(Equal(retry.NewLinearUpTo(time.Second, 2, 3)))
+	})
+
+	It("rejects a spec for a different strategy", func() {
+		var actual retry.LinearUpTo
+		err := json.Unmarshal([]byte(`"constant(wait=1s)"`), &actual)
+		Expect(err).Should(MatchError(retry.ErrWrongStrategy))
+	})
+
+	It("reads any strategy into a Spec", func() {
+		var config struct {
+			Retry retry.Spec `json:"retry"`
+		}
+		err := json.Unmarshal([]byte(`{"retry":"hedged(delay=5ms,max_attempts=2)"}`), &config)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config.Retry.Strategy).Should(Equal(retry.NewHedged(5*time.Millisecond, 2, 0)))
+
+		data, err := json.Marshal(config)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).Should(Equal(`{"retry":"hedged(delay=5ms,max_attempts=2)"}`))
+	})
+
+	It("can't write a strategy with a custom jitter", func() {
+		strategy := retry.NewForever(time.Second)
+		strategy.Jitter = customJitter{}
+		Expect(strategy.String()).Should(Equal("constant(wait=1s,jitter=custom)"))
+		_, err := strategy.MarshalText()
+		Expect(err).Should(MatchError(retry.ErrNotSerializable))
+		_, err = json.Marshal(strategy)
+		Expect(err).Should(MatchError(retry.ErrNotSerializable))
+	})
+
+	It("round-trips a Composed strategy", func() {
+		data, err := json.Marshal(retry.NewComposed(retry.NewConstantBackoff(time.Second), retry.MaxAttempts(2)))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).Should(Equal(`"composed(backoff=constant,wait=1s,max_attempts=2)"`))
+		var actual retry.Composed
+		Expect(json.Unmarshal(data, &actual)).Should(Succeed())
+		Expect(actual.Plan()).Should(Equal(retry.NewUpTo(time.Second, 2).Plan()))
+	})
+
+	It("round-trips a capped Composed strategy", func() {
+		strategy := retry.NewBuilder(retry.NewLinearBackoff(time.Millisecond, 1)).
+			MaxWait(2 * time.Millisecond).
+			StopWhen(retry.MaxAttempts(4)).
+			Build()
+		data, err := json.Marshal(strategy)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).Should(Equal(`"composed(backoff=linear,initial=1ms,growth=1,max_wait=2ms,max_attempts=4)"`))
+		var actual retry.Composed
+		Expect(json.Unmarshal(data, &actual)).Should(Succeed())
+		Expect(actual.Plan()).Should(Equal(strategy.Plan()))
+	})
+
+	DescribeTable("can't write a Composed strategy with parts specs can't describe",
+		func(strategy *retry.Composed) {
+			_, err := json.Marshal(retry.Spec{Strategy: strategy})
+			Expect(err).Should(MatchError(retry.ErrNotSerializable))
+		},
+		Entry("custom backoff", retry.NewComposed(customBackoff{}, nil)),
+		Entry("custom stop policy", retry.NewComposed(nil, customStop{})),
+		Entry("the same stop policy twice", retry.NewComposed(nil, retry.AnyOf{retry.MaxAttempts(2), retry.MaxAttempts(3)})),
+		Entry("a cap of a cap", retry.NewBuilder(retry.NewLinearBackoff(time.Millisecond, 1)).
+			MaxWait(time.Second).
+			MaxWait(2*time.Second).
+			StopWhen(retry.MaxAttempts(3)).
+			Build()),
+		Entry("a cap of no backoff", retry.NewComposed(retry.NewCappedBackoff(nil, time.Second), nil)),
+	)
+})
+
+var _ = Describe("ParseEnv", func() {
+	const key = "GO_RETRY_SPEC_TEST"
+	AfterEach(func() {
+		_ = os.Unsetenv(key)
+	})
+
+	It("returns the fallback when the variable is not set", func() {
+		actual, err := retry.ParseEnv(key, retry.Never)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(actual).Should(BeIdenticalTo(retry.Never))
+	})
+
+	It("parses the variable", func() {
+		Expect(os.Setenv(key, "constant(wait=2s,max_attempts=4)")).Should(Succeed())
+		actual, err := retry.ParseEnv(key, retry.Never)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(actual).Should(Equal(retry.NewUpTo(2*time.Second, 4)))
+	})
+
+	It("names the variable when it is invalid", func() {
+		Expect(os.Setenv(key, "constant(wait=often)")).Should(Succeed())
+		_, err := retry.ParseEnv(key, retry.Never)
+		Expect(err).Should(MatchError(retry.ErrInvalidValue))
+		Expect(err.Error()).Should(HavePrefix(key + ": "))
+	})
+})
+
+// customJitter is a Jitter that specs can't describe
+type customJitter struct{}
+
+func (customJitter) Apply(computed, _ time.Duration) time.Duration {
+	return computed
+}
+
+// customBackoff is a Backoff that specs can't describe
+type customBackoff struct{}
+
+func (customBackoff) Delay(_ uint64) time.Duration {
+	return time.Second
+}
+
+// customStop is a StopPolicy that specs can't describe
+type customStop struct{}
+
+func (customStop) ShouldStop(_ retry.Progress) bool {
+	return true
+}
@@ -0,0 +1,25 @@
+package retry
+
+import "time"
+
+// StopPolicy decides when a strategy should give up retrying. Combine a StopPolicy with a Backoff using a Builder or
+// Composed to make a Strategy
+type StopPolicy interface {
+	// ShouldStop is called after each retryable failure. Return true to give up and return the last error
+	ShouldStop(progress Progress) bool
+}
+
+// Progress describes how far along a strategy is when a StopPolicy is consulted
+type Progress struct {
+	// Attempts is the number of attempts made so far, starting at 1
+	Attempts uint64
+
+	// Elapsed is the time since the first attempt started
+	Elapsed time.Duration
+
+	// TotalWait is the sum of all the waits between attempts so far
+	TotalWait time.Duration
+
+	// NextWait is how long the strategy will wait before the next attempt, if it does not stop
+	NextWait time.Duration
+}
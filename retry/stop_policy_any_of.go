@@ -0,0 +1,13 @@
+package retry
+
+// AnyOf stops as soon as any of its policies would stop. An empty AnyOf never stops
+type AnyOf []StopPolicy
+
+func (a AnyOf) ShouldStop(progress Progress) bool {
+	for _, policy := range a {
+		if policy.ShouldStop(progress) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package retry
+
+// MaxAttempts stops once this many attempts have been made.
+// As with UpTo, 0 and 1 both mean the callback is attempted exactly once
+type MaxAttempts uint64
+
+func (m MaxAttempts) ShouldStop(progress Progress) bool {
+	return progress.Attempts >= uint64(m)
+}
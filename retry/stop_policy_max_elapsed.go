@@ -0,0 +1,10 @@
+package retry
+
+import "time"
+
+// MaxElapsed stops if the next attempt would start after this much time has passed since the first attempt started
+type MaxElapsed time.Duration
+
+func (m MaxElapsed) ShouldStop(progress Progress) bool {
+	return progress.Elapsed+progress.NextWait > time.Duration(m)
+}
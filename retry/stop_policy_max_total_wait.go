@@ -0,0 +1,11 @@
+package retry
+
+import "time"
+
+// MaxTotalWait stops if waiting before the next attempt would make the sum of all waits exceed this duration.
+// Unlike MaxElapsed, the time spent in the attempts themselves is not counted
+type MaxTotalWait time.Duration
+
+func (m MaxTotalWait) ShouldStop(progress Progress) bool {
+	return progress.TotalWait+progress.NextWait > time.Duration(m)
+}
@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"github.com/onsi/gomega"
+	"testing"
+)
+
+func TestStopPolicy_ShouldStop(t *testing.T) {
+	cases := map[string]struct {
+		policy   StopPolicy
+		progress Progress
+		expected bool
+	}{
+		"max attempts under": {
+			policy:   MaxAttempts(3),
+			progress: Progress{Attempts: 2},
+		},
+		"max attempts reached": {
+			policy:   MaxAttempts(3),
+			progress: Progress{Attempts: 3},
+			expected: true,
+		},
+		"max elapsed under": {
+			policy:   MaxElapsed(10 * timeUnit),
+			progress: Progress{Elapsed: 5 * timeUnit, NextWait: 5 * timeUnit},
+		},
+		"max elapsed after next wait": {
+			policy:   MaxElapsed(10 * timeUnit),
+			progress: Progress{Elapsed: 5 * timeUnit, NextWait: 6 * timeUnit},
+			expected: true,
+		},
+		"max total wait under": {
+			policy:   MaxTotalWait(10 * timeUnit),
+			progress: Progress{Elapsed: 100 * timeUnit, TotalWait: 5 * timeUnit, NextWait: 5 * timeUnit},
+		},
+		"max total wait after next wait": {
+			policy:   MaxTotalWait(10 * timeUnit),
+			progress: Progress{TotalWait: 5 * timeUnit, NextWait: 6 * timeUnit},
+			expected: true,
+		},
+		"any of none": {
+			policy:   AnyOf{},
+			progress: Progress{Attempts: 100},
+		},
+		"any of one stops": {
+			policy:   AnyOf{MaxAttempts(200), MaxAttempts(100)},
+			progress: Progress{Attempts: 100},
+			expected: true,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			actual := c.policy.ShouldStop(c.progress)
+			g.Expect(actual).Should(gomega.Equal(c.expected))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package retry_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"github.com/wojnosystems/go-retry/retryTrace"
+	"time"
+)
+
+var _ = Describe("Tracer", func() {
+	var recorder *retryTrace.Recorder
+	BeforeEach(func() {
+		recorder = retryTrace.NewRecorder()
+	})
+
+	It("traces each attempt of a strategy", func() {
+		strategy := retry.NewExponentialUpTo(timeUnit, 2, 3)
+		strategy.Tracer = recorder
+		err := strategy.Retry(context.Background(), func() error {
+			return retryMocks.ErrRetry
+		})
+		Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+		spans := recorder.Spans()
+		Expect(spans).Should(HaveLen(3))
+		for i, span := range spans {
+			Expect(span.Name).Should(Equal(retryTrace.AttemptSpanName))
+			Expect(span.Attributes).Should(HaveKeyWithValue(retryTrace.AttemptKey, int64(i+1)))
+			Expect(span.Attributes).Should(HaveKeyWithValue(retryTrace.OutcomeKey, retryTrace.OutcomeRetry))
+			Expect(span.Ended).Should(BeTrue())
+		}
+		Expect(spans[0].Attributes).Should(HaveKeyWithValue(retryTrace.BackoffKey, 0.0))
+		Expect(spans[1].Attributes).Should(HaveKeyWithValue(retryTrace.BackoffKey, timeUnit.Seconds()))
+	})
+
+	It("traces each hedge", func() {
+		strategy := retry.NewHedged(time.Millisecond, 2, 0)
+		strategy.Tracer = recorder
+		err := strategy.RetryAttempt(context.Background(), func(ctx context.Context, attempt retryLoop.AttemptInfo) error {
+			if attempt.Number == 1 {
+				// slow enough to be hedged, it is canceled once the hedge wins
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return retryError.StopSuccess
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Eventually(func() int {
+			ended := 0
+			for _, span := range recorder.Spans() {
+				if span.Ended {
+					ended++
+				}
+			}
+			return ended
+		}).Should(Equal(2))
+		spans := recorder.Spans()
+		Expect(spans[0].Attributes).Should(HaveKeyWithValue(retryTrace.BackoffKey, 0.0))
+		Expect(spans[1].Attributes).Should(HaveKeyWithValue(retryTrace.BackoffKey, time.Millisecond.Seconds()))
+	})
+})
@@ -3,7 +3,6 @@ package retry
 import (
 	"context"
 	"github.com/wojnosystems/go-retry/retryLoop"
-	"github.com/wojnosystems/go-retry/retrySleep"
 	"time"
 )
 
@@ -15,6 +14,12 @@ type UpTo struct {
 
 	// MaxAttempts is how many failed tries to attempt before returning an error and giving up
 	MaxAttempts uint
+
+	// Jitter randomizes each wait, nil means no jitter
+	Jitter Jitter
+
+	// Options tune how each attempt is made, such as the PerAttemptTimeout, see retryLoop.Options
+	retryLoop.Options
 }
 
 func NewUpTo(
@@ -28,7 +33,66 @@ func NewUpTo(
 }
 
 func (c *UpTo) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
-	return retryLoop.UpTo(ctx, cb, func(i uint64) {
-		retrySleep.WithContext(ctx, c.WaitBetweenAttempts)
-	}, uint64(c.MaxAttempts))
+	return c.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (c *UpTo) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	return c.composed().RetryAttempt(ctx, cb)
+}
+
+// composed is the equivalent Composed strategy
+func (c *UpTo) composed() *Composed {
+	return &Composed{
+		Backoff: &ConstantBackoff{
+			WaitBetweenAttempts: c.WaitBetweenAttempts,
+		},
+		Stop:    MaxAttempts(c.MaxAttempts),
+		Jitter:  c.Jitter,
+		Options: c.Options,
+	}
+}
+
+// Schedule plans at most n waits, see Plan
+func (c *UpTo) Schedule(n int) Plan {
+	return c.composed().Schedule(n)
+}
+
+// Plan plans every wait the strategy would make, see Planner
+func (c *UpTo) Plan() Plan {
+	return c.composed().Plan()
+}
+
+// String is the spec of the strategy, see Parse
+func (c *UpTo) String() string {
+	return c.spec().String()
+}
+
+// spec writes the spec of the strategy
+func (c *UpTo) spec() *specWriter {
+	return newSpecWriter("constant").
+		duration("wait", c.WaitBetweenAttempts).
+		uint("max_attempts", c.MaxAttempts).
+		jitter(c.Jitter).
+		options(c.Options)
+}
+
+// MarshalText writes the spec of the strategy, see Parse. It fails if the strategy has a Jitter from another package
+func (c *UpTo) MarshalText() ([]byte, error) {
+	return c.spec().MarshalText()
+}
+
+// UnmarshalText parses a spec into the strategy, see Parse. The spec must describe a UpTo
+func (c *UpTo) UnmarshalText(text []byte) error {
+	return unmarshalSpec(text, c)
+}
+
+// MarshalJSON writes the spec of the strategy as a JSON string
+func (c *UpTo) MarshalJSON() ([]byte, error) {
+	return marshalJSONSpec(c)
+}
+
+// UnmarshalJSON parses a JSON string containing a spec into the strategy, see UnmarshalText
+func (c *UpTo) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONSpec(data, c)
 }
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/wojnosystems/go-retry/retry"
 	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
 	"github.com/wojnosystems/go-retry/retryMocks"
 	"time"
 )
@@ -60,6 +61,51 @@ var _ = Describe("UpTo", func() {
 			Expect(elapsed).Should(BeNumerically(">=", 4*timeUnit))
 		})
 	})
+	When("using the attempt callback", func() {
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					retryMocks.ErrRetry,
+					retryError.StopSuccess,
+				},
+			}
+		})
+		It("numbers each attempt", func() {
+			subject := retry.NewUpTo(0, 10)
+			err := subject.RetryAttempt(ctx, mock.AttemptGenerator())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mock.Attempts()).Should(HaveLen(2))
+			Expect(mock.Attempts()[1].Number).Should(BeNumerically("==", 2))
+			Expect(mock.Attempts()[1].PreviousErr).Should(Equal(retryMocks.ErrRetryReason))
+		})
+	})
+	When("attempt times out", func() {
+		It("retries the attempt", func() {
+			subject := retry.NewUpTo(0, 10)
+			subject.PerAttemptTimeout = 1 * timeUnit
+			timesRun := 0
+			err := subject.RetryAttempt(ctx, func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+				timesRun++
+				if timesRun < 3 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return retryError.StopSuccess
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(timesRun).Should(Equal(3))
+		})
+	})
+	When("observed", func() {
+		It("notifies the observer", func() {
+			observer := &retryMocks.Observer{}
+			subject := retry.NewUpTo(0, 10)
+			subject.Observer = observer
+			err := subject.Retry(ctx, retryMocks.AlwaysSucceeds)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(observer.Events()).Should(Equal([]string{"start 1", "end 1", "success 1"}))
+		})
+	})
 	When("retries exhausted", func() {
 		It("fails", func() {
 			subject := retry.NewUpTo(0, 1)
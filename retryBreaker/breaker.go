@@ -0,0 +1,168 @@
+package retryBreaker
+
+import (
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"sync"
+	"time"
+)
+
+// Breaker is a circuit breaker. It opens when attempts fail too often, failing further attempts fast until CoolDown
+// passes. Then it lets a trial attempt through to see if the dependency has recovered.
+// A Breaker is safe for concurrent use and is intended to be shared by every call to the same dependency.
+// Do not copy a Breaker after it is first used
+type Breaker struct {
+	// ConsecutiveFailures opens the breaker after this many failures in a row. 0 disables this threshold
+	ConsecutiveFailures uint
+
+	// FailureRate opens the breaker when the fraction of failures among the last Window results reaches it.
+	// 0 disables this threshold
+	FailureRate float64
+
+	// Window is how many of the most recent results FailureRate is measured over. The rate is not checked until
+	// there are this many results
+	Window uint
+
+	// CoolDown is how long the breaker stays open before letting a trial attempt through
+	CoolDown time.Duration
+
+	// Clock, if not nil, is used instead of the system clock to time the CoolDown
+	Clock retrySleep.Clock
+
+	mu               sync.Mutex
+	state            State
+	openedAt         time.Time
+	consecutive      uint
+	results          []bool
+	nextResult       int
+	trialInProgress  bool
+	resultsRecorded  uint
+	failuresInWindow uint
+}
+
+// NewBreaker creates a breaker that opens after consecutiveFailures failures in a row and stays open for coolDown
+func NewBreaker(consecutiveFailures uint, coolDown time.Duration) *Breaker {
+	return &Breaker{
+		ConsecutiveFailures: consecutiveFailures,
+		CoolDown:            coolDown,
+	}
+}
+
+// State returns the current state. An open breaker whose CoolDown has passed reports HalfOpen
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.coolDown()
+	return b.state
+}
+
+// Allow returns nil if an attempt may be made, or ErrOpen if it should fail fast.
+// While half-open, only one trial attempt is allowed at a time.
+// Every allowed attempt must be followed by a call to Success, Failure or Skip
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.coolDown()
+	switch b.state {
+	case Open:
+		return ErrOpen
+	case HalfOpen:
+		if b.trialInProgress {
+			return ErrOpen
+		}
+		b.trialInProgress = true
+	}
+	return nil
+}
+
+// Success records an attempt that succeeded, closing a half-open breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == HalfOpen {
+		b.close()
+		return
+	}
+	b.consecutive = 0
+	b.record(false)
+}
+
+// Failure records an attempt that failed. It returns true if the breaker is now open
+func (b *Breaker) Failure() (opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case HalfOpen:
+		b.open()
+	case Closed:
+		b.consecutive++
+		b.record(true)
+		if b.tripped() {
+			b.open()
+		}
+	}
+	return b.state == Open
+}
+
+// Skip records an attempt whose outcome says nothing about the dependency, such as one canceled by the caller.
+// If the attempt was the half-open trial, another trial is allowed
+func (b *Breaker) Skip() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trialInProgress = false
+}
+
+// tripped is true if either threshold has been reached
+func (b *Breaker) tripped() bool {
+	if b.ConsecutiveFailures != 0 && b.consecutive >= b.ConsecutiveFailures {
+		return true
+	}
+	if b.FailureRate > 0 && b.Window != 0 && b.resultsRecorded >= b.Window {
+		return float64(b.failuresInWindow)/float64(b.Window) >= b.FailureRate
+	}
+	return false
+}
+
+// record adds the result to the FailureRate window, replacing the oldest result when the window is full
+func (b *Breaker) record(failed bool) {
+	if b.Window == 0 {
+		return
+	}
+	if uint(len(b.results)) != b.Window {
+		b.results = make([]bool, b.Window)
+		b.nextResult = 0
+		b.resultsRecorded = 0
+		b.failuresInWindow = 0
+	}
+	if b.resultsRecorded == b.Window {
+		if b.results[b.nextResult] {
+			b.failuresInWindow--
+		}
+	} else {
+		b.resultsRecorded++
+	}
+	b.results[b.nextResult] = failed
+	if failed {
+		b.failuresInWindow++
+	}
+	b.nextResult = (b.nextResult + 1) % len(b.results)
+}
+
+func (b *Breaker) open() {
+	b.state = Open
+	b.openedAt = retrySleep.OrSystem(b.Clock).Now()
+	b.trialInProgress = false
+}
+
+func (b *Breaker) close() {
+	b.state = Closed
+	b.consecutive = 0
+	b.trialInProgress = false
+	b.results = nil
+}
+
+// coolDown moves an open breaker to half-open once the CoolDown has passed
+func (b *Breaker) coolDown() {
+	if b.state == Open && retrySleep.OrSystem(b.Clock).Now().Sub(b.openedAt) >= b.CoolDown {
+		b.state = HalfOpen
+	}
+}
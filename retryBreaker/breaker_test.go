@@ -0,0 +1,96 @@
+package retryBreaker_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryBreaker"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("Breaker", func() {
+	var (
+		clock   *retryMocks.FakeClock
+		subject *retryBreaker.Breaker
+	)
+	BeforeEach(func() {
+		clock = retryMocks.NewFakeClock(time.Now())
+		subject = retryBreaker.NewBreaker(3, 1*time.Minute)
+		subject.Clock = clock
+	})
+
+	It("starts closed", func() {
+		Expect(subject.State()).Should(Equal(retryBreaker.Closed))
+		Expect(subject.Allow()).Should(Succeed())
+	})
+	When("consecutive failures reach the threshold", func() {
+		BeforeEach(func() {
+			Expect(subject.Failure()).Should(BeFalse())
+			Expect(subject.Failure()).Should(BeFalse())
+			Expect(subject.Failure()).Should(BeTrue())
+		})
+		It("opens", func() {
+			Expect(subject.State()).Should(Equal(retryBreaker.Open))
+			Expect(subject.Allow()).Should(MatchError(retryBreaker.ErrOpen))
+		})
+		When("the cool down passes", func() {
+			BeforeEach(func() {
+				clock.Advance(1 * time.Minute)
+			})
+			It("is half-open and allows a single trial", func() {
+				Expect(subject.State()).Should(Equal(retryBreaker.HalfOpen))
+				Expect(subject.Allow()).Should(Succeed())
+				Expect(subject.Allow()).Should(MatchError(retryBreaker.ErrOpen))
+			})
+			It("closes when the trial succeeds", func() {
+				Expect(subject.Allow()).Should(Succeed())
+				subject.Success()
+				Expect(subject.State()).Should(Equal(retryBreaker.Closed))
+			})
+			It("opens again when the trial fails", func() {
+				Expect(subject.Allow()).Should(Succeed())
+				Expect(subject.Failure()).Should(BeTrue())
+				Expect(subject.State()).Should(Equal(retryBreaker.Open))
+			})
+			It("allows another trial when the trial is skipped", func() {
+				Expect(subject.Allow()).Should(Succeed())
+				subject.Skip()
+				Expect(subject.Allow()).Should(Succeed())
+			})
+		})
+	})
+	When("a success interrupts the failures", func() {
+		It("stays closed", func() {
+			subject.Failure()
+			subject.Failure()
+			subject.Success()
+			subject.Failure()
+			Expect(subject.State()).Should(Equal(retryBreaker.Closed))
+		})
+	})
+	When("using a failure rate", func() {
+		BeforeEach(func() {
+			subject = &retryBreaker.Breaker{
+				FailureRate: 0.5,
+				Window:      4,
+				CoolDown:    1 * time.Minute,
+				Clock:       clock,
+			}
+		})
+		It("waits for the window to fill", func() {
+			Expect(subject.Failure()).Should(BeFalse())
+			Expect(subject.Failure()).Should(BeFalse())
+			subject.Success()
+			Expect(subject.Failure()).Should(BeTrue())
+		})
+		It("only counts the most recent results", func() {
+			subject.Failure()
+			subject.Success()
+			subject.Success()
+			subject.Success()
+			// the first failure has left the window
+			Expect(subject.Failure()).Should(BeFalse())
+			Expect(subject.Failure()).Should(BeTrue())
+		})
+	})
+})
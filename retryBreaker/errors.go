@@ -0,0 +1,33 @@
+package retryBreaker
+
+import (
+	"errors"
+)
+
+// ErrOpen is returned instead of calling the callback while the breaker is open.
+// It is not retryable, so the retry stops immediately
+var ErrOpen = errors.New("circuit breaker is open")
+
+// openedBy is returned when an attempt's failure opened the breaker. errors.Is matches ErrOpen and the cause
+type openedBy struct {
+	cause error
+}
+
+func (e *openedBy) Error() string {
+	return ErrOpen.Error() + ": " + e.cause.Error()
+}
+
+// Unwrap returns the error that opened the breaker
+func (e *openedBy) Unwrap() error {
+	return e.cause
+}
+
+// Retryable is false, so the loop stops even though the cause was retryable
+func (e *openedBy) Retryable() bool {
+	return false
+}
+
+// Is matches ErrOpen
+func (e *openedBy) Is(target error) bool {
+	return target == ErrOpen
+}
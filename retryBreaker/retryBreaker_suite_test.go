@@ -0,0 +1,13 @@
+package retryBreaker_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryBreaker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryBreaker Suite")
+}
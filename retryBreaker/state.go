@@ -0,0 +1,26 @@
+package retryBreaker
+
+// State of a Breaker
+type State int
+
+const (
+	// Closed lets all attempts through. This is the normal state
+	Closed State = iota
+	// Open fails all attempts fast, without calling the callback, until the CoolDown has passed
+	Open
+	// HalfOpen lets a single trial attempt through. If it succeeds, the breaker closes, otherwise it opens again
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
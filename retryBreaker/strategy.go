@@ -0,0 +1,60 @@
+package retryBreaker
+
+import (
+	"context"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+)
+
+// Strategy retries using Strategy, but checks Breaker before each attempt. While the breaker is open, the callback
+// is not called and ErrOpen is returned immediately. If an attempt's failure opens the breaker, no more attempts are
+// made and an error matching both ErrOpen and the attempt's error is returned.
+// Retryable errors, including attempts that exceed their PerAttemptTimeout, count as failures.
+// Successes and non-retryable errors count as successes: the dependency answered, even if the answer was an error
+type Strategy struct {
+	retry.Strategy
+	Breaker *Breaker
+
+	// Classifier, if not nil, counts the errors it retries as failures too. Set it to the Classifier of the wrapped
+	// strategy, so the breaker agrees with the strategy about what failed
+	Classifier retryClassify.Classifier
+}
+
+// Wrap protects strategy with breaker
+func Wrap(strategy retry.Strategy, breaker *Breaker) *Strategy {
+	return &Strategy{
+		Strategy: strategy,
+		Breaker:  breaker,
+	}
+}
+
+func (s *Strategy) Retry(ctx context.Context, cb retryLoop.CallbackFunc) (err error) {
+	return s.RetryAttempt(ctx, retryLoop.IgnoreAttempt(cb))
+}
+
+// RetryAttempt is like Retry, but cb is given a context and information for each attempt
+func (s *Strategy) RetryAttempt(ctx context.Context, cb retryLoop.AttemptCallbackFunc) (err error) {
+	return s.Strategy.RetryAttempt(ctx, func(attemptCtx context.Context, attempt retryLoop.AttemptInfo) error {
+		if allowErr := s.Breaker.Allow(); allowErr != nil {
+			return allowErr
+		}
+		attemptErr := cb(attemptCtx, attempt)
+		switch {
+		case ctx.Err() != nil:
+			// the caller gave up, this says nothing about the dependency
+			s.Breaker.Skip()
+		case attemptErr == nil:
+			s.Breaker.Success()
+		case retryError.IsAgain(attemptErr) || attemptCtx.Err() == context.DeadlineExceeded ||
+			s.Classifier.Classify(attemptErr).Retry:
+			if s.Breaker.Failure() {
+				return &openedBy{cause: retryError.UnwrapAgain(attemptErr)}
+			}
+		default:
+			s.Breaker.Success()
+		}
+		return attemptErr
+	})
+}
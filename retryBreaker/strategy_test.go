@@ -0,0 +1,93 @@
+package retryBreaker_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryBreaker"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("Strategy", func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		breaker *retryBreaker.Breaker
+		subject *retryBreaker.Strategy
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+		breaker = retryBreaker.NewBreaker(2, 1*time.Hour)
+		subject = retryBreaker.Wrap(retry.NewUpTo(0, 10), breaker)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+
+	When("the breaker is closed", func() {
+		It("retries normally", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := subject.Retry(ctx, mock.Generator())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(mock.TimesRun()).Should(Equal(2))
+			Expect(breaker.State()).Should(Equal(retryBreaker.Closed))
+		})
+		It("does not count non-retryable errors as failures", func() {
+			for i := 0; i < 3; i++ {
+				err := subject.Retry(ctx, retryMocks.AlwaysFails)
+				Expect(err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+			}
+			Expect(breaker.State()).Should(Equal(retryBreaker.Closed))
+		})
+		It("counts errors retried by the classifier as failures", func() {
+			classifier := func(err error) retryClassify.Decision {
+				if err == retryMocks.ErrThatCannotBeRetried {
+					return retryClassify.Retry
+				}
+				return retryClassify.Stop
+			}
+			strategy := retry.NewUpTo(0, 10)
+			strategy.Classifier = classifier
+			subject = retryBreaker.Wrap(strategy, breaker)
+			subject.Classifier = classifier
+			err := subject.Retry(ctx, retryMocks.AlwaysFails)
+			Expect(err).Should(MatchError(retryBreaker.ErrOpen))
+			Expect(err).Should(MatchError(retryMocks.ErrThatCannotBeRetried))
+		})
+	})
+	When("failures open the breaker", func() {
+		It("stops retrying", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := subject.Retry(ctx, mock.Generator())
+			Expect(err).Should(MatchError(retryBreaker.ErrOpen))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(mock.TimesRun()).Should(Equal(2))
+		})
+	})
+	When("the breaker is open", func() {
+		BeforeEach(func() {
+			breaker.Failure()
+			breaker.Failure()
+		})
+		It("fails fast without calling the callback", func() {
+			wasCalled := false
+			err := subject.Retry(ctx, func() error {
+				wasCalled = true
+				return nil
+			})
+			Expect(err).Should(Equal(retryBreaker.ErrOpen))
+			Expect(wasCalled).Should(BeFalse())
+		})
+	})
+})
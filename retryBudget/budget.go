@@ -0,0 +1,119 @@
+package retryBudget
+
+import (
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"math"
+	"sync/atomic"
+)
+
+// DefaultMaxSaved is the MaxSaved used by NewBudget
+const DefaultMaxSaved = 100
+
+// scale is how many parts each retry is split into, so that fractions of a retry earned by requests add up exactly
+const scale = 1000
+
+// Budget limits retries to a fraction of requests, to avoid retry storms when a dependency is failing.
+// Each request earns Ratio retries, and each retry spends 1. When nothing has been earned, MinPerSecond retries are
+// still allowed each second so that callers making few requests can retry.
+// Set it as the Budget of any strategy, see retryLoop.Options. Share one Budget between every call to a dependency,
+// usually for the whole process. It is safe for concurrent use and never blocks.
+// The zero value allows no retries. Do not copy a Budget after it is first used
+type Budget struct {
+	// Ratio is how many retries each request earns. 0.1 lets retries be at most 10% of requests
+	Ratio float64
+
+	// MinPerSecond is how many retries are allowed each second once the retries earned by requests are spent
+	MinPerSecond uint32
+
+	// MaxSaved caps how many earned retries can be saved up, so that many requests followed by an outage cannot
+	// cause a storm of retries. 0 means there is no cap
+	MaxSaved uint
+
+	// Clock, if not nil, is used instead of the system clock to tell when each second starts
+	Clock retrySleep.Clock
+
+	// saved is how many retries have been earned and not spent, in parts of a retry, see scale
+	saved int64
+
+	// second packs the second, in the high 32 bits, and how many MinPerSecond retries were spent during it, in the
+	// low 32 bits, so both can be swapped together
+	second uint64
+}
+
+// NewBudget creates a budget that lets retries be ratio of requests, plus minPerSecond retries each second.
+// At most DefaultMaxSaved earned retries are saved up
+func NewBudget(ratio float64, minPerSecond uint32) *Budget {
+	return &Budget{
+		Ratio:        ratio,
+		MinPerSecond: minPerSecond,
+		MaxSaved:     DefaultMaxSaved,
+	}
+}
+
+// Requested earns Ratio retries. The loop calls it once each time it starts
+func (b *Budget) Requested() {
+	earned := int64(math.Round(b.Ratio * scale))
+	if earned <= 0 {
+		return
+	}
+	if b.MaxSaved == 0 {
+		atomic.AddInt64(&b.saved, earned)
+		return
+	}
+	max := int64(b.MaxSaved) * scale
+	for {
+		saved := atomic.LoadInt64(&b.saved)
+		if saved >= max {
+			return
+		}
+		next := saved + earned
+		if next > max {
+			next = max
+		}
+		if atomic.CompareAndSwapInt64(&b.saved, saved, next) {
+			return
+		}
+	}
+}
+
+// Withdraw spends an earned retry or, when none are left, one of this second's MinPerSecond retries.
+// It returns false if neither is available. The loop calls it before each retry
+func (b *Budget) Withdraw() bool {
+	for {
+		saved := atomic.LoadInt64(&b.saved)
+		if saved < scale {
+			break
+		}
+		if atomic.CompareAndSwapInt64(&b.saved, saved, saved-scale) {
+			return true
+		}
+	}
+	return b.withdrawPerSecond()
+}
+
+// Saved is how many earned retries can still be spent. It may be fractional
+func (b *Budget) Saved() float64 {
+	return float64(atomic.LoadInt64(&b.saved)) / scale
+}
+
+// withdrawPerSecond spends one of the current second's MinPerSecond retries, if any are left
+func (b *Budget) withdrawPerSecond() bool {
+	if b.MinPerSecond == 0 {
+		return false
+	}
+	now := uint32(retrySleep.OrSystem(b.Clock).Now().Unix())
+	for {
+		packed := atomic.LoadUint64(&b.second)
+		second, spent := uint32(packed>>32), uint32(packed)
+		if second != now {
+			// a new second has started, nothing has been spent during it
+			spent = 0
+		}
+		if spent >= b.MinPerSecond {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&b.second, packed, uint64(now)<<32|uint64(spent+1)) {
+			return true
+		}
+	}
+}
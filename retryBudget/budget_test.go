@@ -0,0 +1,109 @@
+package retryBudget_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryBudget"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ retryLoop.Budget = &retryBudget.Budget{}
+
+var _ = Describe("Budget", func() {
+	var (
+		clock   *retryMocks.FakeClock
+		subject *retryBudget.Budget
+	)
+	BeforeEach(func() {
+		clock = retryMocks.NewFakeClock(time.Unix(1000, 0))
+		subject = retryBudget.NewBudget(0.1, 0)
+		subject.Clock = clock
+	})
+
+	It("allows no retries before any requests", func() {
+		Expect(subject.Withdraw()).Should(BeFalse())
+	})
+	It("allows a retry for every 10 requests", func() {
+		for i := 0; i < 25; i++ {
+			subject.Requested()
+		}
+		Expect(subject.Saved()).Should(BeNumerically("~", 2.5))
+		Expect(subject.Withdraw()).Should(BeTrue())
+		Expect(subject.Withdraw()).Should(BeTrue())
+		Expect(subject.Withdraw()).Should(BeFalse())
+	})
+	It("caps the retries saved up", func() {
+		subject.MaxSaved = 2
+		for i := 0; i < 100; i++ {
+			subject.Requested()
+		}
+		Expect(subject.Saved()).Should(BeNumerically("~", 2))
+	})
+	When("a minimum per second is allowed", func() {
+		BeforeEach(func() {
+			subject.MinPerSecond = 2
+		})
+		It("allows that many each second", func() {
+			Expect(subject.Withdraw()).Should(BeTrue())
+			Expect(subject.Withdraw()).Should(BeTrue())
+			Expect(subject.Withdraw()).Should(BeFalse())
+			clock.Advance(1 * time.Second)
+			Expect(subject.Withdraw()).Should(BeTrue())
+		})
+		It("spends earned retries first", func() {
+			for i := 0; i < 10; i++ {
+				subject.Requested()
+			}
+			Expect(subject.Withdraw()).Should(BeTrue())
+			Expect(subject.Withdraw()).Should(BeTrue())
+			Expect(subject.Withdraw()).Should(BeTrue())
+			Expect(subject.Withdraw()).Should(BeFalse())
+		})
+	})
+	It("is safe for concurrent use", func() {
+		subject.MaxSaved = 0
+		subject.MinPerSecond = 5
+		var wg sync.WaitGroup
+		var allowed int64
+		for g := 0; g < 1000; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 10; i++ {
+					subject.Requested()
+				}
+				for i := 0; i < 3; i++ {
+					if subject.Withdraw() {
+						atomic.AddInt64(&allowed, 1)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		// 10000 requests earn 1000 retries, plus 5 for this second
+		Expect(allowed).Should(BeNumerically("==", 1005))
+	})
+	When("used by a strategy", func() {
+		It("stops retrying when the budget is exhausted", func() {
+			subject.MinPerSecond = 1
+			strategy := retry.NewUpTo(0, 10)
+			strategy.Budget = subject
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := strategy.Retry(context.Background(), mock.Generator())
+			Expect(err).Should(MatchError(retryError.ErrBudgetExhausted))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(mock.TimesRun()).Should(Equal(2))
+		})
+	})
+})
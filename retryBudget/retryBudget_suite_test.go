@@ -0,0 +1,13 @@
+package retryBudget_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryBudget(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryBudget Suite")
+}
@@ -0,0 +1,50 @@
+package retryClassify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// NetTimeout retries network operations that timed out, which are net.Error with Timeout true
+func NetTimeout(err error) Decision {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return Retry
+	}
+	return Stop
+}
+
+// ConnectionRefused retries errors caused by syscall.ECONNREFUSED, usually because the server is not listening yet
+func ConnectionRefused(err error) Decision {
+	return retryIfIs(err, syscall.ECONNREFUSED)
+}
+
+// ConnectionReset retries errors caused by syscall.ECONNRESET, when the other side dropped the connection
+func ConnectionReset(err error) Decision {
+	return retryIfIs(err, syscall.ECONNRESET)
+}
+
+// UnexpectedEOF retries errors caused by io.ErrUnexpectedEOF, when a connection ended part way through a message
+func UnexpectedEOF(err error) Decision {
+	return retryIfIs(err, io.ErrUnexpectedEOF)
+}
+
+// AttemptTimeout retries errors caused by context.DeadlineExceeded. Classifiers are only consulted while the loop's
+// context is alive, so the deadline that passed belongs to the attempt, such as one set by PerAttemptTimeout or by the
+// callback itself
+func AttemptTimeout(err error) Decision {
+	return retryIfIs(err, context.DeadlineExceeded)
+}
+
+// Transient retries the errors retried by each of the built-in classifiers in this package
+var Transient = Any(NetTimeout, ConnectionRefused, ConnectionReset, UnexpectedEOF, AttemptTimeout)
+
+func retryIfIs(err, target error) Decision {
+	if errors.Is(err, target) {
+		return Retry
+	}
+	return Stop
+}
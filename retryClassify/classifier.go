@@ -0,0 +1,45 @@
+package retryClassify
+
+import (
+	"time"
+)
+
+// Decision is what a Classifier decided to do about an error
+type Decision struct {
+	// Retry is true if the error should be retried
+	Retry bool
+
+	// After, if greater than 0, is how long to wait before retrying, instead of the wait calculated by the strategy.
+	// It is only used if Retry is true
+	After time.Duration
+}
+
+var (
+	// Retry retries the error after the wait calculated by the strategy
+	Retry = Decision{Retry: true}
+
+	// Stop does not retry the error
+	Stop = Decision{}
+)
+
+// RetryAfter retries the error after wait, instead of the wait calculated by the strategy
+func RetryAfter(wait time.Duration) Decision {
+	return Decision{
+		Retry: true,
+		After: wait,
+	}
+}
+
+// Classifier decides whether an error returned by an attempt should be retried. This lets errors you don't control,
+// such as those from third-party libraries, be retried without wrapping them in retryError.Again.
+// Set one as the Classifier of any strategy, see retryLoop.Options.
+// Classifiers are only given errors that are not already retryable, and are never given nil
+type Classifier func(err error) Decision
+
+// Classify decides what to do about err using the classifier. A nil classifier stops
+func (c Classifier) Classify(err error) Decision {
+	if c == nil {
+		return Stop
+	}
+	return c(err)
+}
@@ -0,0 +1,163 @@
+package retryClassify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onsi/gomega"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var errFake = errors.New("fake")
+
+// timeoutErr is a net.Error, like those returned by net.Conn when a deadline passes
+type timeoutErr struct {
+	timeout bool
+}
+
+func (e timeoutErr) Error() string   { return "i/o timeout" }
+func (e timeoutErr) Timeout() bool   { return e.timeout }
+func (e timeoutErr) Temporary() bool { return false }
+
+func alwaysRetries(_ error) Decision {
+	return Retry
+}
+
+func alwaysStops(_ error) Decision {
+	return Stop
+}
+
+func retriesAfter(wait time.Duration) Classifier {
+	return func(_ error) Decision {
+		return RetryAfter(wait)
+	}
+}
+
+func TestBuiltIn(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	cases := map[string]struct {
+		classifier Classifier
+		err        error
+		expected   Decision
+	}{
+		"net timeout": {
+			classifier: NetTimeout,
+			err:        &net.OpError{Op: "read", Net: "tcp", Err: timeoutErr{timeout: true}},
+			expected:   Retry,
+		},
+		"net error that is not a timeout": {
+			classifier: NetTimeout,
+			err:        timeoutErr{},
+			expected:   Stop,
+		},
+		"connection refused": {
+			classifier: ConnectionRefused,
+			err:        refused,
+			expected:   Retry,
+		},
+		"connection refused is not reset": {
+			classifier: ConnectionReset,
+			err:        refused,
+			expected:   Stop,
+		},
+		"connection reset": {
+			classifier: ConnectionReset,
+			err:        reset,
+			expected:   Retry,
+		},
+		"unexpected EOF": {
+			classifier: UnexpectedEOF,
+			err:        fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF),
+			expected:   Retry,
+		},
+		"EOF is expected": {
+			classifier: UnexpectedEOF,
+			err:        io.EOF,
+			expected:   Stop,
+		},
+		"attempt timeout": {
+			classifier: AttemptTimeout,
+			err:        fmt.Errorf("query: %w", context.DeadlineExceeded),
+			expected:   Retry,
+		},
+		"canceled is not a timeout": {
+			classifier: AttemptTimeout,
+			err:        context.Canceled,
+			expected:   Stop,
+		},
+		"transient": {
+			classifier: Transient,
+			err:        reset,
+			expected:   Retry,
+		},
+		"not transient": {
+			classifier: Transient,
+			err:        errFake,
+			expected:   Stop,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(c.classifier(c.err)).Should(gomega.Equal(c.expected))
+		})
+	}
+}
+
+func TestCompose(t *testing.T) {
+	cases := map[string]struct {
+		classifier Classifier
+		expected   Decision
+	}{
+		"any with none": {
+			classifier: Any(),
+			expected:   Stop,
+		},
+		"any with one retrying": {
+			classifier: Any(alwaysStops, retriesAfter(time.Second), alwaysRetries),
+			expected:   RetryAfter(time.Second),
+		},
+		"any with all stopping": {
+			classifier: Any(alwaysStops, alwaysStops),
+			expected:   Stop,
+		},
+		"all with none": {
+			classifier: All(),
+			expected:   Stop,
+		},
+		"all retrying waits the longest": {
+			classifier: All(retriesAfter(time.Second), alwaysRetries, retriesAfter(time.Minute)),
+			expected:   RetryAfter(time.Minute),
+		},
+		"all with one stopping": {
+			classifier: All(alwaysRetries, alwaysStops),
+			expected:   Stop,
+		},
+		"not retrying": {
+			classifier: Not(alwaysRetries),
+			expected:   Stop,
+		},
+		"not stopping": {
+			classifier: Not(alwaysStops),
+			expected:   Retry,
+		},
+		"nil": {
+			classifier: Any(nil),
+			expected:   Stop,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(c.classifier(errFake)).Should(gomega.Equal(c.expected))
+		})
+	}
+}
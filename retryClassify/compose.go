@@ -0,0 +1,44 @@
+package retryClassify
+
+// Any retries if any of the classifiers retry. The first of them to retry decides how long to wait
+func Any(classifiers ...Classifier) Classifier {
+	return func(err error) Decision {
+		for _, classifier := range classifiers {
+			if decision := classifier.Classify(err); decision.Retry {
+				return decision
+			}
+		}
+		return Stop
+	}
+}
+
+// All retries only if every one of the classifiers retries. The longest wait any of them asked for is used.
+// With no classifiers, it stops
+func All(classifiers ...Classifier) Classifier {
+	return func(err error) Decision {
+		if len(classifiers) == 0 {
+			return Stop
+		}
+		decided := Retry
+		for _, classifier := range classifiers {
+			decision := classifier.Classify(err)
+			if !decision.Retry {
+				return Stop
+			}
+			if decision.After > decided.After {
+				decided.After = decision.After
+			}
+		}
+		return decided
+	}
+}
+
+// Not retries what classifier stops, and stops what it retries
+func Not(classifier Classifier) Classifier {
+	return func(err error) Decision {
+		if classifier.Classify(err).Retry {
+			return Stop
+		}
+		return Retry
+	}
+}
@@ -1,5 +1,9 @@
 package retryError
 
+import (
+	"errors"
+)
+
 type AgainWrapper interface {
 	// error includes the Error method, forcing AgainWrapper to also be an error type
 	error
@@ -31,11 +35,38 @@ func (a *again) Unwrap() error {
 	return a.wrapped
 }
 
-// IsAgain returns true if this error should be retried by the retry library, false otherwise
+// Retryable is implemented by errors that know whether they should be retried. Any error may implement it, so that
+// it can be retried without wrapping it in Again
+type Retryable interface {
+	// Retryable is true if the error should be retried
+	Retryable() bool
+}
+
+// Retryable is true, errors wrapped by Again are always retried
+func (a *again) Retryable() bool {
+	return true
+}
+
+// IsAgain returns true if this error should be retried by the retry library, false otherwise.
+// The error chain is searched with errors.As, so errors that wrap a retryable error, such as with fmt.Errorf and %w,
+// are retryable too. The first error in the chain that implements Retryable decides. Again and AgainAfter are
+// Retryable. The errors returned by the retry loop, such as AttemptsExhausted, are not Retryable, so a retry that
+// fails inside the attempt of another retry does not make the outer one retry, unless you wrap it in Again
 func IsAgain(err error) bool {
-	if err == nil {
-		return false
+	var retryable Retryable
+	return errors.As(err, &retryable) && retryable.Retryable()
+}
+
+// UnwrapAgain removes the Again or AgainAfter wrapper from err, if err is one of them, and returns the error they
+// wrapped. Any other error is returned as-is, even if it wraps an Again, so that the context added by the wrapping is
+// kept. The retry loop uses this for the errors it returns and gives to the next attempt
+func UnwrapAgain(err error) error {
+	switch v := err.(type) {
+	case *again:
+		return v.Unwrap()
+	case *againAfter:
+		return v.Unwrap()
+	default:
+		return err
 	}
-	_, ok := err.(*again)
-	return ok
 }
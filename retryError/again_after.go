@@ -0,0 +1,44 @@
+package retryError
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryAfterHint may be implemented by errors that know how long to wait before trying again, such as an error for
+// an HTTP 429 or 503 response with a Retry-After header. If an error wrapped by Again implements this, the retry loop
+// waits for the hinted duration instead of the duration calculated by the retry strategy
+type RetryAfterHint interface {
+	// RetryAfter is how long to wait before trying again
+	RetryAfter() time.Duration
+}
+
+// AgainAfter is like Again, but also tells the library to wait for the duration of wait before trying again,
+// instead of the duration calculated by the retry strategy
+func AgainAfter(err error, wait time.Duration) AgainWrapper {
+	return &againAfter{
+		again: again{
+			wrapped: err,
+		},
+		wait: wait,
+	}
+}
+
+type againAfter struct {
+	again
+	wait time.Duration
+}
+
+// RetryAfter is the wait given to the AgainAfter constructor
+func (a *againAfter) RetryAfter() time.Duration {
+	return a.wait
+}
+
+// RetryAfter returns the wait hinted by err or any error it wraps. ok is false if there is no hint
+func RetryAfter(err error) (wait time.Duration, ok bool) {
+	var hint RetryAfterHint
+	if !errors.As(err, &hint) {
+		return 0, false
+	}
+	return hint.RetryAfter(), true
+}
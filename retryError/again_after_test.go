@@ -0,0 +1,62 @@
+package retryError
+
+import (
+	"fmt"
+	. "github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+type hintedErr struct {
+	wait time.Duration
+}
+
+func (h *hintedErr) Error() string {
+	return "hinted"
+}
+
+func (h *hintedErr) RetryAfter() time.Duration {
+	return h.wait
+}
+
+func TestAgainAfter(t *testing.T) {
+	g := NewWithT(t)
+	err := AgainAfter(errFake, 5*time.Second)
+	g.Expect(err.Unwrap()).Should(Equal(errFake))
+	g.Expect(err.Error()).Should(Equal(errFake.Error()))
+	g.Expect(IsAgain(err)).Should(BeTrue())
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		input        error
+		expectedWait time.Duration
+		expectedOk   bool
+	}{
+		"nil": {
+			input: nil,
+		},
+		"no hint": {
+			input: Again(errFake),
+		},
+		"again after": {
+			input:        AgainAfter(errFake, 3*time.Second),
+			expectedWait: 3 * time.Second,
+			expectedOk:   true,
+		},
+		"wrapped error hints": {
+			input:        Again(fmt.Errorf("request failed: %w", &hintedErr{wait: 7 * time.Second})),
+			expectedWait: 7 * time.Second,
+			expectedOk:   true,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := NewWithT(t)
+			wait, ok := RetryAfter(c.input)
+			g.Expect(wait).Should(Equal(c.expectedWait))
+			g.Expect(ok).Should(Equal(c.expectedOk))
+		})
+	}
+}
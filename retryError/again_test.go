@@ -2,8 +2,10 @@ package retryError
 
 import (
 	"errors"
+	"fmt"
 	. "github.com/onsi/gomega"
 	"testing"
+	"time"
 )
 
 var errFake = errors.New("fake")
@@ -36,6 +38,27 @@ func TestAgain_IsAgain(t *testing.T) {
 		"not retryable": {
 			input: errFake,
 		},
+		"retryable after": {
+			input:    AgainAfter(errFake, time.Second),
+			expected: true,
+		},
+		"wrapped retryable": {
+			input:    fmt.Errorf("middleware: %w", Again(errFake)),
+			expected: true,
+		},
+		"implements retryable": {
+			input:    retryableErr{retryable: true},
+			expected: true,
+		},
+		"implements not retryable": {
+			input: retryableErr{},
+		},
+		"not retryable wrapping retryable": {
+			input: retryableErr{wrapped: Again(errFake)},
+		},
+		"exhausted": {
+			input: AttemptsExhausted(fmt.Errorf("middleware: %w", Again(errFake))),
+		},
 	}
 
 	for caseName, c := range cases {
@@ -46,3 +69,53 @@ func TestAgain_IsAgain(t *testing.T) {
 		})
 	}
 }
+
+func TestUnwrapAgain(t *testing.T) {
+	wrapped := fmt.Errorf("middleware: %w", Again(errFake))
+	cases := map[string]struct {
+		input    error
+		expected error
+	}{
+		"again": {
+			input:    Again(errFake),
+			expected: errFake,
+		},
+		"again after": {
+			input:    AgainAfter(errFake, time.Second),
+			expected: errFake,
+		},
+		"wrapped again is kept whole": {
+			input:    wrapped,
+			expected: wrapped,
+		},
+		"not retryable": {
+			input:    errFake,
+			expected: errFake,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(UnwrapAgain(c.input)).Should(Equal(c.expected))
+		})
+	}
+}
+
+// retryableErr decides whether it is retryable, regardless of what it wraps
+type retryableErr struct {
+	retryable bool
+	wrapped   error
+}
+
+func (e retryableErr) Error() string {
+	return "retryable"
+}
+
+func (e retryableErr) Retryable() bool {
+	return e.retryable
+}
+
+func (e retryableErr) Unwrap() error {
+	return e.wrapped
+}
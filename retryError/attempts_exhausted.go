@@ -0,0 +1,48 @@
+package retryError
+
+import (
+	"errors"
+)
+
+// ErrAttemptsExhausted is matched by errors.Is when the retry loop gave up because no more attempts were allowed
+var ErrAttemptsExhausted = errors.New("retry attempts exhausted")
+
+// AttemptsExhausted wraps the error from the last attempt to indicate that the retry loop ran out of attempts.
+// errors.Is matches both ErrAttemptsExhausted and lastCause
+func AttemptsExhausted(lastCause error) error {
+	return &exhausted{
+		sentinel:  ErrAttemptsExhausted,
+		lastCause: lastCause,
+	}
+}
+
+// exhausted is the error from the last attempt, marked with the sentinel error for why the loop gave up, such as
+// ErrAttemptsExhausted
+type exhausted struct {
+	sentinel  error
+	lastCause error
+}
+
+func (e *exhausted) Error() string {
+	return e.sentinel.Error() + ": " + e.lastCause.Error()
+}
+
+// Unwrap returns the error from the last attempt
+func (e *exhausted) Unwrap() error {
+	return e.lastCause
+}
+
+// Is matches the sentinel
+func (e *exhausted) Is(target error) bool {
+	return target == e.sentinel
+}
+
+// LastCause is the error from the last attempt
+func (e *exhausted) LastCause() error {
+	return e.lastCause
+}
+
+// Retryable is false, the loop has already given up
+func (e *exhausted) Retryable() bool {
+	return false
+}
@@ -0,0 +1,76 @@
+package retryError
+
+import (
+	"context"
+	"errors"
+	. "github.com/onsi/gomega"
+	"net"
+	"testing"
+)
+
+func TestAttemptsExhausted(t *testing.T) {
+	g := NewWithT(t)
+	err := AttemptsExhausted(errFake)
+	g.Expect(err.Error()).Should(Equal("retry attempts exhausted: fake"))
+	g.Expect(errors.Is(err, ErrAttemptsExhausted)).Should(BeTrue())
+	g.Expect(errors.Is(err, errFake)).Should(BeTrue())
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).Should(BeFalse())
+}
+
+func TestContextDone(t *testing.T) {
+	g := NewWithT(t)
+	err := ContextDone(context.DeadlineExceeded, typedErr{})
+	g.Expect(err.Error()).Should(Equal("context deadline exceeded, last error: typed"))
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).Should(BeTrue())
+	g.Expect(errors.Is(err, typedErr{})).Should(BeTrue())
+	g.Expect(errors.Is(err, ErrAttemptsExhausted)).Should(BeFalse())
+	var target typedErr
+	g.Expect(errors.As(err, &target)).Should(BeTrue())
+}
+
+func TestContextDone_AsMatchesTheContextFirst(t *testing.T) {
+	g := NewWithT(t)
+	err := ContextDone(context.DeadlineExceeded, &net.DNSError{Err: "timeout", IsTimeout: true})
+	var target net.Error
+	g.Expect(errors.As(err, &target)).Should(BeTrue())
+	g.Expect(target).Should(Equal(context.DeadlineExceeded))
+}
+
+func TestLastCause(t *testing.T) {
+	cases := map[string]struct {
+		input    error
+		expected error
+	}{
+		"exhausted": {
+			input:    AttemptsExhausted(errFake),
+			expected: errFake,
+		},
+		"budget exhausted": {
+			input:    BudgetExhausted(errFake),
+			expected: errFake,
+		},
+		"deadline too close": {
+			input:    DeadlineTooClose(errFake),
+			expected: errFake,
+		},
+		"context done": {
+			input:    ContextDone(context.Canceled, errFake),
+			expected: errFake,
+		},
+		"with history": {
+			input:    &Exhausted{Err: AttemptsExhausted(errFake)},
+			expected: errFake,
+		},
+		"other": {
+			input:    errFake,
+			expected: errFake,
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(LastCause(c.input)).Should(Equal(c.expected))
+		})
+	}
+}
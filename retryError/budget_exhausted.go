@@ -0,0 +1,18 @@
+package retryError
+
+import (
+	"errors"
+)
+
+// ErrBudgetExhausted is matched by errors.Is when the retry loop gave up because its retry budget would not allow
+// another retry
+var ErrBudgetExhausted = errors.New("retry budget exhausted")
+
+// BudgetExhausted wraps the error from the last attempt to indicate that the retry loop stopped because the retry
+// budget ran out. errors.Is matches both ErrBudgetExhausted and lastCause
+func BudgetExhausted(lastCause error) error {
+	return &exhausted{
+		sentinel:  ErrBudgetExhausted,
+		lastCause: lastCause,
+	}
+}
@@ -0,0 +1,16 @@
+package retryError
+
+import (
+	"errors"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestBudgetExhausted(t *testing.T) {
+	g := NewWithT(t)
+	err := BudgetExhausted(errFake)
+	g.Expect(err.Error()).Should(Equal("retry budget exhausted: fake"))
+	g.Expect(errors.Is(err, ErrBudgetExhausted)).Should(BeTrue())
+	g.Expect(errors.Is(err, errFake)).Should(BeTrue())
+	g.Expect(errors.Is(err, ErrAttemptsExhausted)).Should(BeFalse())
+}
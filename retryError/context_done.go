@@ -0,0 +1,49 @@
+package retryError
+
+import (
+	"errors"
+)
+
+// ContextDone wraps the error of a context that expired or was canceled while retrying, along with the error from
+// the last retryable attempt. errors.Is and errors.As match ctxErr first, then lastCause
+func ContextDone(ctxErr, lastCause error) error {
+	return &contextDone{
+		ctxErr:    ctxErr,
+		lastCause: lastCause,
+	}
+}
+
+type contextDone struct {
+	ctxErr    error
+	lastCause error
+}
+
+func (e *contextDone) Error() string {
+	return e.ctxErr.Error() + ", last error: " + e.lastCause.Error()
+}
+
+// Unwrap returns the context's error
+func (e *contextDone) Unwrap() error {
+	return e.ctxErr
+}
+
+// Is matches the error from the last attempt
+func (e *contextDone) Is(target error) bool {
+	return errors.Is(e.lastCause, target)
+}
+
+// As finds the first error that matches target in the context's error, then in the error from the last attempt.
+// errors.As calls As before Unwrap, so As has to look at the context's error itself for it to come first
+func (e *contextDone) As(target interface{}) bool {
+	return errors.As(e.ctxErr, target) || errors.As(e.lastCause, target)
+}
+
+// LastCause is the error from the last retryable attempt
+func (e *contextDone) LastCause() error {
+	return e.lastCause
+}
+
+// Retryable is false, the loop has already given up
+func (e *contextDone) Retryable() bool {
+	return false
+}
@@ -0,0 +1,18 @@
+package retryError
+
+import (
+	"errors"
+)
+
+// ErrDeadlineTooClose is matched by errors.Is when the retry loop gave up early because the time left before the
+// context's deadline was too short to wait and make another attempt
+var ErrDeadlineTooClose = errors.New("not enough time left before the deadline to retry")
+
+// DeadlineTooClose wraps the error from the last attempt to indicate that the retry loop stopped because another
+// attempt would not finish before the context's deadline. errors.Is matches both ErrDeadlineTooClose and lastCause
+func DeadlineTooClose(lastCause error) error {
+	return &exhausted{
+		sentinel:  ErrDeadlineTooClose,
+		lastCause: lastCause,
+	}
+}
@@ -0,0 +1,18 @@
+package retryError
+
+import (
+	"context"
+	"errors"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestDeadlineTooClose(t *testing.T) {
+	g := NewWithT(t)
+	err := DeadlineTooClose(errFake)
+	g.Expect(err.Error()).Should(Equal("not enough time left before the deadline to retry: fake"))
+	g.Expect(errors.Is(err, ErrDeadlineTooClose)).Should(BeTrue())
+	g.Expect(errors.Is(err, errFake)).Should(BeTrue())
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).Should(BeFalse())
+	g.Expect(IsAgain(err)).Should(BeFalse())
+}
@@ -0,0 +1,66 @@
+package retryError
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Attempt records the outcome of a single failed attempt
+type Attempt struct {
+	// Err is the error returned by the attempt, without the Again wrapper
+	Err error
+
+	// StartedAt is when the attempt started
+	StartedAt time.Time
+
+	// Wait is how long the loop waited after the attempt before trying again. It is 0 for the last attempt
+	Wait time.Duration
+}
+
+// Exhausted is returned by the retry loop when it gives up and the history of attempts was requested
+// (see retryLoop.Options.KeepHistory). errors.Is and errors.As match Err as well as the error of any of the Attempts
+type Exhausted struct {
+	// Attempts are all the failed attempts, in the order they were made
+	Attempts []Attempt
+
+	// Err is the error that would have been returned without the history
+	Err error
+}
+
+func (e *Exhausted) Error() string {
+	if len(e.Attempts) == 1 {
+		return fmt.Sprintf("%s (after 1 attempt)", e.Err.Error())
+	}
+	return fmt.Sprintf("%s (after %d attempts)", e.Err.Error(), len(e.Attempts))
+}
+
+// Unwrap returns the error that would have been returned without the history
+func (e *Exhausted) Unwrap() error {
+	return e.Err
+}
+
+// Is is true if target matches the error of any of the Attempts
+func (e *Exhausted) Is(target error) bool {
+	for _, attempt := range e.Attempts {
+		if errors.Is(attempt.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in Attempts that matches target
+func (e *Exhausted) As(target interface{}) bool {
+	for _, attempt := range e.Attempts {
+		if errors.As(attempt.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retryable is false, the loop has already given up
+func (e *Exhausted) Retryable() bool {
+	return false
+}
@@ -0,0 +1,86 @@
+package retryError
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+type typedErr struct{}
+
+func (typedErr) Error() string {
+	return "typed"
+}
+
+func TestExhausted_Error(t *testing.T) {
+	cases := map[string]struct {
+		attempts []Attempt
+		expected string
+	}{
+		"one attempt": {
+			attempts: []Attempt{{Err: errFake}},
+			expected: "fake (after 1 attempt)",
+		},
+		"several attempts": {
+			attempts: []Attempt{{Err: errFake}, {Err: errFake}},
+			expected: "fake (after 2 attempts)",
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := NewWithT(t)
+			err := &Exhausted{
+				Attempts: c.attempts,
+				Err:      errFake,
+			}
+			g.Expect(err.Error()).Should(Equal(c.expected))
+		})
+	}
+}
+
+func TestExhausted_Is(t *testing.T) {
+	errFirst := errors.New("first")
+	err := error(&Exhausted{
+		Attempts: []Attempt{
+			{Err: fmt.Errorf("wrapped: %w", errFirst)},
+			{Err: typedErr{}},
+			{Err: errFake},
+		},
+		Err: errFake,
+	})
+	cases := map[string]struct {
+		target   error
+		expected bool
+	}{
+		"first attempt": {
+			target:   errFirst,
+			expected: true,
+		},
+		"last attempt": {
+			target:   errFake,
+			expected: true,
+		},
+		"not attempted": {
+			target: errors.New("other"),
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(errors.Is(err, c.target)).Should(Equal(c.expected))
+		})
+	}
+}
+
+func TestExhausted_As(t *testing.T) {
+	g := NewWithT(t)
+	err := error(&Exhausted{
+		Attempts: []Attempt{{Err: typedErr{}}, {Err: errFake}},
+		Err:      errFake,
+	})
+	var target typedErr
+	g.Expect(errors.As(err, &target)).Should(BeTrue())
+}
@@ -0,0 +1,17 @@
+package retryError
+
+import (
+	"errors"
+)
+
+// LastCause returns the error from the last attempt if err was returned because attempts or the retry budget were
+// exhausted, or the context was done. Otherwise, it returns err
+func LastCause(err error) error {
+	var caused interface {
+		LastCause() error
+	}
+	if errors.As(err, &caused) {
+		return caused.LastCause()
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+package retryHTTP_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryHTTP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryHTTP Suite")
+}
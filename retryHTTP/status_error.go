@@ -0,0 +1,51 @@
+package retryHTTP
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusError is the error retried when a response has one of the RetryableStatusCodes
+type StatusError struct {
+	// StatusCode is the response's status code, such as 503
+	StatusCode int
+
+	// Status is the response's status, such as "503 Service Unavailable"
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return "retryable HTTP status: " + e.Status
+}
+
+// maxRetryAfterSeconds is the longest Retry-After, in seconds, that fits in a time.Duration
+const maxRetryAfterSeconds = int64(math.MaxInt64 / time.Second)
+
+// parseRetryAfter reads the Retry-After header, which is either a number of seconds or an HTTP date.
+// ok is false if there is no header or it can't be read
+func parseRetryAfter(header string, now time.Time) (wait time.Duration, ok bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(header, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > maxRetryAfterSeconds {
+			// any wait this long is capped by the context or MaxRetryAfter anyway, just don't overflow
+			seconds = maxRetryAfterSeconds
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	wait = at.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
@@ -0,0 +1,55 @@
+package retryHTTP
+
+import (
+	. "github.com/onsi/gomega"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 11, 5, 0, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		header       string
+		expectedWait time.Duration
+		expectedOk   bool
+	}{
+		"missing": {
+			header: "",
+		},
+		"seconds": {
+			header:       "120",
+			expectedWait: 2 * time.Minute,
+			expectedOk:   true,
+		},
+		"seconds too large for a duration": {
+			header:       "9223372036854775807",
+			expectedWait: time.Duration(maxRetryAfterSeconds) * time.Second,
+			expectedOk:   true,
+		},
+		"negative seconds": {
+			header: "-1",
+		},
+		"date": {
+			header:       "Fri, 05 Nov 2021 00:00:30 GMT",
+			expectedWait: 30 * time.Second,
+			expectedOk:   true,
+		},
+		"date in the past": {
+			header:       "Thu, 04 Nov 2021 23:00:00 GMT",
+			expectedWait: 0,
+			expectedOk:   true,
+		},
+		"garbage": {
+			header: "soon",
+		},
+	}
+
+	for caseName, c := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			g := NewWithT(t)
+			wait, ok := parseRetryAfter(c.header, now)
+			g.Expect(ok).Should(Equal(c.expectedOk))
+			g.Expect(wait).Should(Equal(c.expectedWait))
+		})
+	}
+}
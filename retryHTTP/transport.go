@@ -0,0 +1,242 @@
+package retryHTTP
+
+import (
+	"context"
+	"errors"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryableStatusCodes are the status codes retried when RetryableStatusCodes is nil
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ErrNoAttempts is returned if the Strategy did not make any attempts, so there is no response to return
+var ErrNoAttempts = errors.New("retry strategy made no attempts")
+
+// maxDrain is how much of a retried response's body is read so that the connection can be reused
+const maxDrain = 64 * 1024
+
+// Transport is an http.RoundTripper that retries requests using Strategy.
+// Responses with one of the RetryableStatusCodes and network errors that may be transient, which are refused or reset
+// connections and timeouts, are retried. A Retry-After header on a retried response is used as the wait before the
+// next attempt, capped by the strategy's MaxRetryAfter, if it has one.
+// Only idempotent requests are retried unless RetryNonIdempotent is set: GET, HEAD, OPTIONS, TRACE, PUT, DELETE and
+// requests with an Idempotency-Key header. Requests with a body are only retried if they have GetBody to rewind it,
+// which http.NewRequest sets for common body types.
+// If attempts run out while the server keeps returning a retryable status, the last response is returned with no
+// error, as it would be without retrying.
+// The strategy's PerAttemptTimeout limits how long each attempt waits for the response. Once a response arrives,
+// reading its body is only limited by the request's context. Strategy must make one attempt at a time, so Hedged
+// cannot be used
+type Transport struct {
+	// Base makes each attempt. nil uses http.DefaultTransport
+	Base http.RoundTripper
+
+	// Strategy decides when to retry and how long to wait
+	Strategy retry.Strategy
+
+	// RetryableStatusCodes are the response status codes to retry. nil uses DefaultRetryableStatusCodes
+	RetryableStatusCodes []int
+
+	// RetryNonIdempotent, if true, also retries requests that are not idempotent, such as POST
+	RetryNonIdempotent bool
+}
+
+// NewTransport creates a Transport that makes requests with base and retries them with strategy
+func NewTransport(base http.RoundTripper, strategy retry.Strategy) *Transport {
+	return &Transport{
+		Base:     base,
+		Strategy: strategy,
+	}
+}
+
+// RoundTrip makes the request, retrying it according to Strategy.
+// If the strategy gives up while the server keeps returning a retryable status, such as when attempts run out, the
+// last response is returned with a nil error, as it would be without retrying, so check its StatusCode. The error is
+// only returned if there is no response to return, such as when the connection failed or the context is done
+func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	if !t.canRetry(req) {
+		return t.base().RoundTrip(req)
+	}
+	ctx := req.Context()
+	attempts := 0
+	err = t.Strategy.RetryAttempt(ctx, func(attemptCtx context.Context, _ retryLoop.AttemptInfo) error {
+		if resp != nil {
+			// the last response is being retried, let its connection be reused
+			discard(resp)
+			resp = nil
+		}
+		attemptReq, attemptErr := rewind(req, attempts)
+		attempts++
+		if attemptErr != nil {
+			return attemptErr
+		}
+		resp, attemptErr = t.send(attemptCtx, attemptReq)
+		if attemptErr != nil {
+			resp = nil
+			if ctx.Err() == nil && isRetryableNetworkError(attemptErr) {
+				return retryError.Again(attemptErr)
+			}
+			return attemptErr
+		}
+		if !t.isRetryableStatus(resp.StatusCode) {
+			return retryError.StopSuccess
+		}
+		statusErr := &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return retryError.AgainAfter(statusErr, wait)
+		}
+		return retryError.Again(statusErr)
+	})
+	if resp != nil && (err == nil || ctx.Err() == nil) {
+		// either it succeeded, or attempts ran out on a retryable status and the caller gets the last response
+		return resp, nil
+	}
+	if resp != nil {
+		discard(resp)
+	}
+	if err == nil {
+		// the strategy never called the callback, such as retry.Skip
+		err = ErrNoAttempts
+	}
+	return nil, err
+}
+
+// send makes an attempt with req. attemptCtx only limits how long it waits for the response, as it is canceled once
+// the attempt returns, which is before the caller reads the body. The body is limited by req's context instead
+func (t *Transport) send(attemptCtx context.Context, req *http.Request) (*http.Response, error) {
+	reqCtx, cancel := context.WithCancel(req.Context())
+	received := make(chan struct{})
+	timedOut := make(chan bool, 1)
+	go func() {
+		select {
+		case <-attemptCtx.Done():
+			cancel()
+			timedOut <- true
+		case <-received:
+			timedOut <- false
+		}
+	}()
+	resp, err := t.base().RoundTrip(req.WithContext(reqCtx))
+	close(received)
+	if <-timedOut {
+		if resp != nil {
+			discard(resp)
+		}
+		return nil, attemptCtx.Err()
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a request once its response's body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// canRetry is true if req may be sent more than once
+func (t *Transport) canRetry(req *http.Request) bool {
+	if t.Strategy == nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body can't be sent again
+		return false
+	}
+	return t.RetryNonIdempotent || isIdempotent(req)
+}
+
+func (t *Transport) isRetryableStatus(statusCode int) bool {
+	codes := t.RetryableStatusCodes
+	if codes == nil {
+		codes = DefaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotent is true if sending req more than once has the same effect as sending it once, see RFC 7231 4.2.2
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, hasKey := req.Header["Idempotency-Key"]
+	_, hasXKey := req.Header["X-Idempotency-Key"]
+	return hasKey || hasXKey
+}
+
+// rewind returns the request to send for the attempt. The first attempt sends req itself, the rest send a copy with
+// a new body from GetBody
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 {
+		return req, nil
+	}
+	attemptReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attemptReq.Body = body
+	}
+	return attemptReq, nil
+}
+
+// transientNetworkError retries network errors that may go away by themselves: timeouts, and connections that were
+// refused or reset. Errors that will happen again, such as a host name that can't be resolved, are not retried
+var transientNetworkError = retryClassify.Any(retryClassify.NetTimeout, retryClassify.ConnectionRefused, retryClassify.ConnectionReset)
+
+// isRetryableNetworkError is true if err means the request could not be sent or the connection failed in a way that
+// sending it again may succeed
+func isRetryableNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// the server closed the connection, usually a reused connection it had timed out
+		return true
+	}
+	return transientNetworkError(err).Retry
+}
+
+// discard reads some of the body, so the connection may be reused, then closes it
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+	_ = resp.Body.Close()
+}
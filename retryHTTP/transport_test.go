@@ -0,0 +1,252 @@
+package retryHTTP_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryBudget"
+	"github.com/wojnosystems/go-retry/retryHTTP"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// server responds with each status in turn, repeating the last one, and records each request's body
+type server struct {
+	statuses   []int
+	retryAfter string
+	hangUp     bool
+	stall      time.Duration
+
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.bodies = append(s.bodies, string(body))
+	hit := len(s.bodies)
+	s.mu.Unlock()
+	if s.stall > 0 && hit == 1 {
+		select {
+		case <-time.After(s.stall):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if s.hangUp && hit == 1 {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		_ = conn.Close()
+		return
+	}
+	status := s.statuses[len(s.statuses)-1]
+	if hit <= len(s.statuses) {
+		status = s.statuses[hit-1]
+	}
+	if s.retryAfter != "" {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, http.StatusText(status))
+}
+
+func (s *server) hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+// roundTripperFunc makes each request by calling the function
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("Transport", func() {
+	var (
+		ctx      context.Context
+		cancel   context.CancelFunc
+		handler  *server
+		ts       *httptest.Server
+		strategy *retry.UpTo
+		observer *retryMocks.Observer
+		client   *http.Client
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		handler = &server{}
+		ts = httptest.NewServer(handler)
+		strategy = retry.NewUpTo(0, 3)
+		observer = &retryMocks.Observer{}
+		strategy.Observer = observer
+		client = &http.Client{Transport: retryHTTP.NewTransport(nil, strategy)}
+	})
+	AfterEach(func() {
+		ts.Close()
+		cancel()
+	})
+	get := func() (*http.Response, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		return client.Do(req)
+	}
+
+	When("the server is unavailable at first", func() {
+		It("retries until it succeeds", func() {
+			handler.statuses = []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+			body, _ := io.ReadAll(resp.Body)
+			Expect(string(body)).Should(Equal("OK"))
+			Expect(handler.hits()).Should(Equal(3))
+		})
+	})
+	When("the server never recovers", func() {
+		It("returns the last response", func() {
+			handler.statuses = []int{http.StatusTooManyRequests}
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusTooManyRequests))
+			body, _ := io.ReadAll(resp.Body)
+			Expect(string(body)).Should(Equal("Too Many Requests"))
+			Expect(handler.hits()).Should(Equal(3))
+			Expect(observer.Events()).Should(ContainElement("give up attempts_exhausted"))
+		})
+		It("returns the last response when the budget runs out", func() {
+			handler.statuses = []int{http.StatusServiceUnavailable}
+			strategy.Budget = &retryBudget.Budget{}
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusServiceUnavailable))
+			Expect(handler.hits()).Should(Equal(1))
+			Expect(observer.Events()).Should(ContainElement("give up budget_exhausted"))
+		})
+	})
+	When("the status is not retryable", func() {
+		It("does not retry", func() {
+			handler.statuses = []int{http.StatusInternalServerError}
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusInternalServerError))
+			Expect(handler.hits()).Should(Equal(1))
+		})
+	})
+	When("the server sends Retry-After", func() {
+		It("waits for it", func() {
+			handler.statuses = []int{http.StatusServiceUnavailable, http.StatusOK}
+			handler.retryAfter = "120"
+			strategy.MaxRetryAfter = 5 * time.Millisecond
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(observer.Waits()).Should(Equal([]time.Duration{5 * time.Millisecond}))
+		})
+	})
+	When("the connection fails", func() {
+		It("retries", func() {
+			handler.statuses = []int{http.StatusOK}
+			handler.hangUp = true
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+			Expect(handler.hits()).Should(Equal(2))
+		})
+	})
+	When("an attempt takes longer than the PerAttemptTimeout", func() {
+		It("retries it, and the body of the response can still be read", func() {
+			handler.statuses = []int{http.StatusOK}
+			handler.stall = 5 * time.Second
+			strategy.PerAttemptTimeout = 50 * time.Millisecond
+			resp, err := get()
+			Expect(err).Should(BeNil())
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).Should(BeNil())
+			Expect(string(body)).Should(Equal("OK"))
+			Expect(handler.hits()).Should(Equal(2))
+			Expect(observer.Events()).Should(ContainElement("end 1 context deadline exceeded"))
+		})
+	})
+	When("the server is not listening", func() {
+		It("retries, then returns the error", func() {
+			ts.Close()
+			_, err := get()
+			Expect(err).ShouldNot(BeNil())
+			Expect(observer.Events()).Should(ContainElement("give up attempts_exhausted"))
+		})
+	})
+	DescribeTable("retries network errors that may be transient",
+		func(networkErr error, expectedAttempts int) {
+			attempts := 0
+			client.Transport.(*retryHTTP.Transport).Base = roundTripperFunc(func(_ *http.Request) (*http.Response, error) {
+				attempts++
+				return nil, networkErr
+			})
+			_, err := get()
+			Expect(err).Should(MatchError(networkErr))
+			Expect(attempts).Should(Equal(expectedAttempts))
+		},
+		Entry("connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, 3),
+		Entry("connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, 3),
+		Entry("timeout", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, 3),
+		Entry("host not found", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, 1),
+		Entry("unreachable address", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.EADDRNOTAVAIL}, 1),
+	)
+	When("the request is not idempotent", func() {
+		post := func() (*http.Response, error) {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL, strings.NewReader("payload"))
+			return client.Do(req)
+		}
+		BeforeEach(func() {
+			handler.statuses = []int{http.StatusServiceUnavailable, http.StatusOK}
+		})
+		It("does not retry by default", func() {
+			resp, err := post()
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusServiceUnavailable))
+			Expect(handler.hits()).Should(Equal(1))
+		})
+		It("retries when allowed, sending the body again", func() {
+			client.Transport.(*retryHTTP.Transport).RetryNonIdempotent = true
+			resp, err := post()
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(resp.StatusCode).Should(Equal(http.StatusOK))
+			Expect(handler.bodies).Should(Equal([]string{"payload", "payload"}))
+		})
+		It("retries when it has an idempotency key", func() {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL, strings.NewReader("payload"))
+			req.Header.Set("Idempotency-Key", "abc")
+			resp, err := client.Do(req)
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(handler.hits()).Should(Equal(2))
+		})
+	})
+	When("the body cannot be rewound", func() {
+		It("does not retry", func() {
+			handler.statuses = []int{http.StatusServiceUnavailable, http.StatusOK}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodPut, ts.URL, io.NopCloser(strings.NewReader("payload")))
+			resp, err := client.Do(req)
+			Expect(err).Should(BeNil())
+			_ = resp.Body.Close()
+			Expect(handler.hits()).Should(Equal(1))
+		})
+	})
+})
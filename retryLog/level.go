@@ -0,0 +1,30 @@
+package retryLog
+
+// Level is how important a log line is
+type Level int
+
+const (
+	// LevelDebug is for details only needed when investigating a problem
+	LevelDebug Level = iota
+	// LevelInfo is for normal events, such as a retry
+	LevelInfo
+	// LevelWarn is for events that may need attention, such as giving up
+	LevelWarn
+	// LevelError is for failures
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,93 @@
+package retryLog
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Logger writes structured log lines. Implement it to send retry events to the logging library you use.
+// keyvals alternates keys, which are strings, and their values, such as "attempt", 2, "wait", time.Second
+type Logger interface {
+	Log(level Level, msg string, keyvals ...interface{})
+}
+
+// LoggerFunc adapts a function into a Logger
+type LoggerFunc func(level Level, msg string, keyvals ...interface{})
+
+func (f LoggerFunc) Log(level Level, msg string, keyvals ...interface{}) {
+	f(level, msg, keyvals...)
+}
+
+// StdLogger writes to a log.Logger from the standard library, one line per call in the key=value style, such as
+// level=info msg=retrying attempt=1 err="connection refused" wait=100ms elapsed=2ms
+type StdLogger struct {
+	// Logger is written to, nil uses the standard logger of the log package
+	Logger *log.Logger
+
+	// MinLevel is the least important level written, lines below it are dropped
+	MinLevel Level
+}
+
+// NewStdLogger creates a StdLogger that writes lines of LevelInfo and above to logger
+func NewStdLogger(logger *log.Logger) *StdLogger {
+	return &StdLogger{
+		Logger:   logger,
+		MinLevel: LevelInfo,
+	}
+}
+
+func (l *StdLogger) Log(level Level, msg string, keyvals ...interface{}) {
+	if level < l.MinLevel {
+		return
+	}
+	line := Format(level, msg, keyvals...)
+	if l.Logger == nil {
+		log.Print(line)
+		return
+	}
+	l.Logger.Print(line)
+}
+
+// Format writes a log line in the key=value style used by StdLogger. Values that contain spaces, quotes or '=' are
+// quoted. A key without a value is given the value "(MISSING)"
+func Format(level Level, msg string, keyvals ...interface{}) string {
+	var line strings.Builder
+	line.WriteString("level=")
+	line.WriteString(level.String())
+	line.WriteString(" msg=")
+	line.WriteString(formatValue(msg))
+	for i := 0; i < len(keyvals); i += 2 {
+		line.WriteByte(' ')
+		line.WriteString(fmt.Sprint(keyvals[i]))
+		line.WriteByte('=')
+		if i+1 < len(keyvals) {
+			line.WriteString(formatValue(keyvals[i+1]))
+		} else {
+			line.WriteString("(MISSING)")
+		}
+	}
+	return line.String()
+}
+
+// formatValue writes value, quoting it if it would be ambiguous otherwise
+func formatValue(value interface{}) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		text = "nil"
+	case string:
+		text = v
+	case error:
+		text = v.Error()
+	case fmt.Stringer:
+		text = v.String()
+	default:
+		text = fmt.Sprint(v)
+	}
+	if text == "" || strings.ContainsAny(text, " =\"\t\n") {
+		return strconv.Quote(text)
+	}
+	return text
+}
@@ -0,0 +1,46 @@
+package retryLog_test
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryLog"
+	"log"
+	"time"
+)
+
+var _ = Describe("StdLogger", func() {
+	var (
+		out    *bytes.Buffer
+		logger *retryLog.StdLogger
+	)
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		logger = retryLog.NewStdLogger(log.New(out, "", 0))
+	})
+
+	It("writes key=value lines", func() {
+		logger.Log(retryLog.LevelInfo, "retrying", "attempt", 2, "err", errors.New("connection refused"), "wait", 100*time.Millisecond)
+		Expect(out.String()).Should(Equal("level=info msg=retrying attempt=2 err=\"connection refused\" wait=100ms\n"))
+	})
+
+	It("drops lines below MinLevel", func() {
+		logger.Log(retryLog.LevelDebug, "hidden")
+		Expect(out.String()).Should(BeEmpty())
+		logger.MinLevel = retryLog.LevelDebug
+		logger.Log(retryLog.LevelDebug, "shown")
+		Expect(out.String()).Should(Equal("level=debug msg=shown\n"))
+	})
+})
+
+var _ = Describe("Format", func() {
+	It("quotes ambiguous values", func() {
+		Expect(retryLog.Format(retryLog.LevelWarn, "giving up", "a", "", "b", `say "hi"`, "c", "x=y", "d", nil)).
+			Should(Equal(`level=warn msg="giving up" a="" b="say \"hi\"" c="x=y" d=nil`))
+	})
+
+	It("marks a key without a value", func() {
+		Expect(retryLog.Format(retryLog.LevelError, "odd", "key")).Should(Equal("level=error msg=odd key=(MISSING)"))
+	})
+})
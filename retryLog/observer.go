@@ -0,0 +1,77 @@
+package retryLog
+
+import (
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"time"
+)
+
+// Observer logs each retry and give up of a loop. Set it as the Observer of any strategy, see retryLoop.Options.
+// Each retry is logged with the message "retrying" and the keys attempt, err, wait and elapsed, where err is the
+// error of the attempt that failed and elapsed is the time since the retry started. Each give up is logged with the
+// message "giving up" and the keys attempt, reason, err and elapsed, where err is the error returned by the retry.
+// If Name is set, it comes first with the key strategy
+type Observer struct {
+	retryLoop.NopObserver
+
+	// Logger writes the log lines, nil writes them to the standard logger of the log package, like a zero StdLogger
+	Logger Logger
+
+	// Name, if not empty, is logged with each line so that the strategy can be told apart from others
+	Name string
+
+	// RetryLevel is the level retries are logged at
+	RetryLevel Level
+
+	// GiveUpLevel is the level give ups are logged at
+	GiveUpLevel Level
+
+	// Sampler, if not nil, decides which retries are logged. Give ups are always logged, because each is an error
+	// returned to the caller
+	Sampler Sampler
+}
+
+// NewObserver creates an Observer that logs retries at LevelInfo and give ups at LevelWarn to logger
+func NewObserver(logger Logger) *Observer {
+	return &Observer{
+		Logger:      logger,
+		RetryLevel:  LevelInfo,
+		GiveUpLevel: LevelWarn,
+	}
+}
+
+func (o *Observer) OnWait(attempt retryLoop.AttemptInfo, wait time.Duration) {
+	if o.Sampler != nil && !o.Sampler.Sample() {
+		return
+	}
+	o.log(o.RetryLevel, "retrying",
+		"attempt", attempt.Number,
+		"err", attempt.Err,
+		"wait", wait,
+		"elapsed", elapsed(attempt),
+	)
+}
+
+func (o *Observer) OnGiveUp(attempt retryLoop.AttemptInfo, reason retryLoop.GiveUpReason, err error) {
+	o.log(o.GiveUpLevel, "giving up",
+		"attempt", attempt.Number,
+		"reason", reason,
+		"err", err,
+		"elapsed", elapsed(attempt),
+	)
+}
+
+func (o *Observer) log(level Level, msg string, keyvals ...interface{}) {
+	if o.Name != "" {
+		keyvals = append([]interface{}{"strategy", o.Name}, keyvals...)
+	}
+	logger := o.Logger
+	if logger == nil {
+		logger = &StdLogger{}
+	}
+	logger.Log(level, msg, keyvals...)
+}
+
+// elapsed is the time from the start of the loop to the end of attempt
+func elapsed(attempt retryLoop.AttemptInfo) time.Duration {
+	return attempt.Elapsed + attempt.Duration
+}
@@ -0,0 +1,95 @@
+package retryLog_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLog"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"log"
+	"os"
+	"time"
+)
+
+// recordingLogger keeps each line it is given
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Log(level retryLog.Level, msg string, keyvals ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(level, " ", msg, " ", keyvals))
+}
+
+var _ = Describe("Observer", func() {
+	var (
+		logger   *recordingLogger
+		observer *retryLog.Observer
+		clock    *retryMocks.FakeClock
+		strategy *retry.UpTo
+	)
+	BeforeEach(func() {
+		logger = &recordingLogger{}
+		observer = retryLog.NewObserver(logger)
+		clock = retryMocks.NewFakeClock(time.Now())
+		strategy = retry.NewUpTo(0, 3)
+		strategy.Observer = observer
+		strategy.Clock = clock
+	})
+
+	failing := func() error {
+		clock.Advance(time.Second)
+		return retryMocks.ErrRetry
+	}
+
+	It("logs each retry and the give up", func() {
+		_ = strategy.Retry(context.Background(), failing)
+		Expect(logger.lines).Should(Equal([]string{
+			"info retrying [attempt 1 err forced retry wait 0s elapsed 1s]",
+			"info retrying [attempt 2 err forced retry wait 0s elapsed 2s]",
+			"warn giving up [attempt 3 reason attempts_exhausted err retry attempts exhausted: forced retry elapsed 3s]",
+		}))
+	})
+
+	It("logs to the standard logger when there is no Logger", func() {
+		var written bytes.Buffer
+		log.SetOutput(&written)
+		defer log.SetOutput(os.Stderr)
+		strategy.Observer = &retryLog.Observer{}
+		_ = strategy.Retry(context.Background(), failing)
+		Expect(written.String()).Should(ContainSubstring("level=debug msg=retrying attempt=1"))
+		Expect(written.String()).Should(ContainSubstring("level=debug msg=\"giving up\" attempt=3"))
+	})
+
+	It("logs nothing on success", func() {
+		_ = strategy.Retry(context.Background(), func() error {
+			return retryError.StopSuccess
+		})
+		Expect(logger.lines).Should(BeEmpty())
+	})
+
+	It("names the strategy and uses the configured levels", func() {
+		observer.Name = "db"
+		observer.RetryLevel = retryLog.LevelDebug
+		observer.GiveUpLevel = retryLog.LevelError
+		strategy.MaxAttempts = 2
+		_ = strategy.Retry(context.Background(), failing)
+		Expect(logger.lines).Should(Equal([]string{
+			"debug retrying [strategy db attempt 1 err forced retry wait 0s elapsed 1s]",
+			"error giving up [strategy db attempt 2 reason attempts_exhausted err retry attempts exhausted: forced retry elapsed 2s]",
+		}))
+	})
+
+	It("samples retries but not give ups", func() {
+		observer.Sampler = retryLog.NewEveryN(2)
+		strategy.MaxAttempts = 4
+		_ = strategy.Retry(context.Background(), failing)
+		Expect(logger.lines).Should(HaveLen(3))
+		Expect(logger.lines[0]).Should(HavePrefix("info retrying [attempt 1 "))
+		Expect(logger.lines[1]).Should(HavePrefix("info retrying [attempt 3 "))
+		Expect(logger.lines[2]).Should(HavePrefix("warn giving up"))
+	})
+})
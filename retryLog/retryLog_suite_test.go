@@ -0,0 +1,13 @@
+package retryLog_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryLog(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryLog Suite")
+}
@@ -0,0 +1,68 @@
+package retryLog
+
+import (
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether each retry is logged, to avoid flooding the logs when many calls are retrying at once.
+// It must be safe for concurrent use
+type Sampler interface {
+	// Sample returns true if the event should be logged
+	Sample() bool
+}
+
+// EveryN logs the first of every N events. N of 0 or 1 logs every event. It is safe for concurrent use
+type EveryN struct {
+	N uint64
+
+	seen uint64
+}
+
+// NewEveryN creates a sampler that logs the first of every n events
+func NewEveryN(n uint64) *EveryN {
+	return &EveryN{
+		N: n,
+	}
+}
+
+func (s *EveryN) Sample() bool {
+	seen := atomic.AddUint64(&s.seen, 1)
+	return s.N <= 1 || (seen-1)%s.N == 0
+}
+
+// PerSecond logs at most Max events each second and drops the rest. It is safe for concurrent use
+type PerSecond struct {
+	Max uint
+
+	// Clock, if not nil, is used instead of the system clock to tell when each second starts
+	Clock retrySleep.Clock
+
+	mu     sync.Mutex
+	second time.Time
+	logged uint
+}
+
+// NewPerSecond creates a sampler that logs at most max events each second
+func NewPerSecond(max uint) *PerSecond {
+	return &PerSecond{
+		Max: max,
+	}
+}
+
+func (s *PerSecond) Sample() bool {
+	second := retrySleep.OrSystem(s.Clock).Now().Truncate(time.Second)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !second.Equal(s.second) {
+		s.second = second
+		s.logged = 0
+	}
+	if s.logged >= s.Max {
+		return false
+	}
+	s.logged++
+	return true
+}
@@ -0,0 +1,39 @@
+package retryLog_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryLog"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("EveryN", func() {
+	It("samples the first of every N", func() {
+		sampler := retryLog.NewEveryN(3)
+		var sampled []bool
+		for i := 0; i < 7; i++ {
+			sampled = append(sampled, sampler.Sample())
+		}
+		Expect(sampled).Should(Equal([]bool{true, false, false, true, false, false, true}))
+	})
+
+	It("samples everything when N is 0", func() {
+		sampler := &retryLog.EveryN{}
+		Expect(sampler.Sample()).Should(BeTrue())
+		Expect(sampler.Sample()).Should(BeTrue())
+	})
+})
+
+var _ = Describe("PerSecond", func() {
+	It("samples at most Max each second", func() {
+		clock := retryMocks.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		sampler := retryLog.NewPerSecond(2)
+		sampler.Clock = clock
+		Expect(sampler.Sample()).Should(BeTrue())
+		Expect(sampler.Sample()).Should(BeTrue())
+		Expect(sampler.Sample()).Should(BeFalse())
+		clock.Advance(time.Second)
+		Expect(sampler.Sample()).Should(BeTrue())
+	})
+})
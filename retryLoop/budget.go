@@ -0,0 +1,11 @@
+package retryLoop
+
+// Budget limits how many retries may be made, usually across every call in the process, to avoid retry storms.
+// Implementations must be safe for concurrent use. See retryBudget.Budget
+type Budget interface {
+	// Requested is called once each time a loop starts, before its first attempt
+	Requested()
+
+	// Withdraw is called before each retry. If it returns false, the retry is not made and the loop gives up
+	Withdraw() bool
+}
@@ -0,0 +1,35 @@
+package retryLoop
+
+import (
+	"github.com/wojnosystems/go-retry/retryError"
+	"time"
+)
+
+// history records each failed attempt for Options.KeepHistory
+type history struct {
+	attempts []retryError.Attempt
+}
+
+// failed records an attempt that started at startedAt and returned err
+func (h *history) failed(startedAt time.Time, err error) {
+	h.attempts = append(h.attempts, retryError.Attempt{
+		Err:       retryError.UnwrapAgain(err),
+		StartedAt: startedAt,
+	})
+}
+
+// waited records how long the loop waited after the last attempt
+func (h *history) waited(wait time.Duration) {
+	h.attempts[len(h.attempts)-1].Wait = wait
+}
+
+// exhausted wraps err with the history, if any attempts were made
+func (h *history) exhausted(err error) error {
+	if len(h.attempts) == 0 {
+		return err
+	}
+	return &retryError.Exhausted{
+		Attempts: h.attempts,
+		Err:      err,
+	}
+}
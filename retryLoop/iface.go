@@ -1,11 +1,44 @@
 package retryLoop
 
+import (
+	"context"
+	"time"
+)
+
 // CallbackFunc is called each time a retryable attempt needs to be made
 // return nil AKA retryStop.Success to stop retrying
 // return retryAgain.Error(err) to retry. If no more attempts can be made, the Error will be returned to the caller
 // return any other error to stop retrying and return the error immediately
 type CallbackFunc func() (err error)
 
+// AttemptCallbackFunc is like CallbackFunc, but is given a context for this attempt and information about the attempt.
+// ctx is a child of the context given to the loop and is canceled as soon as the callback returns.
+// The returned error follows the same rules as CallbackFunc
+type AttemptCallbackFunc func(ctx context.Context, attempt AttemptInfo) (err error)
+
+// AttemptInfo describes the attempt being made to an AttemptCallbackFunc
+type AttemptInfo struct {
+	// Number is the attempt being made, starting at 1. Like timesAttempted, it stops counting at math.MaxUint64
+	Number uint64
+
+	// Elapsed is the time since the loop started
+	Elapsed time.Duration
+
+	// PreviousErr is the error, without the retryError.Again wrapper, returned by the previous attempt.
+	// It is nil on the first attempt
+	PreviousErr error
+
+	// Err is the error, without the retryError.Again wrapper, returned by this attempt. Like Duration, it is only set
+	// once the attempt has ended, so it is always nil for the callback and is set for the Observer from OnAttemptEnd on
+	Err error
+
+	// Duration is how long this attempt took, see Err
+	Duration time.Duration
+
+	// Waited is how long the loop waited before this attempt. It is 0 for the first attempt
+	Waited time.Duration
+}
+
 // WaitBetweenAttemptsFunc is called after a retryable error is received and there are additional retry attempts
 // permitted. If there are no retry attempts, this method will not be called.
 // timesWaited is the current number of wait that have been requested (starts at 0).
@@ -14,6 +47,10 @@ type CallbackFunc func() (err error)
 // number of times the request failed.
 type WaitBetweenAttemptsFunc func(timesWaited uint64)
 
+// DelayFunc is like WaitBetweenAttemptsFunc, but instead of waiting, it returns how long the loop should wait.
+// The loop will then wait that long, or until the context is done, whichever occurs first
+type DelayFunc func(timesWaited uint64) time.Duration
+
 // ShouldContinueLoopingFunc should return true if another retry should be attempted, false to stop
 // This method is only called if an error wrapped in a retryError.Again is returned
 // timesAttempted will return the number of times the call-back has been attempted (starts at 1) and will count up until it reaches the maximum uint64 size, at which point it will stop counting, but continue calling your method
@@ -0,0 +1,48 @@
+package retryLoop
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// maxLatencySamples is how many of the most recent attempt durations are kept to estimate the next one
+const maxLatencySamples = 64
+
+// expectedLatencyPercentile is the percentile of past attempt durations used as the expected duration of the next
+const expectedLatencyPercentile = 0.9
+
+// latencies keeps the durations of the most recent attempts of a single loop
+type latencies struct {
+	samples []time.Duration
+	next    int
+}
+
+// add records the duration of an attempt, replacing the oldest once maxLatencySamples are kept
+func (l *latencies) add(d time.Duration) {
+	if len(l.samples) < maxLatencySamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % maxLatencySamples
+}
+
+// percentile returns the duration that fraction p of the samples are no longer than, using the nearest rank.
+// It is 0 if there are no samples
+func (l *latencies) percentile(p float64) time.Duration {
+	if len(l.samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(l.samples))
+	copy(sorted, l.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,106 @@
+package retryLoop
+
+import (
+	"time"
+)
+
+// Observer is notified as the loop makes attempts, waits, and stops. Use it to log or count retries.
+// Observers are called synchronously from the loop, so they should return quickly
+type Observer interface {
+	// OnAttemptStart is called just before the callback is called
+	OnAttemptStart(attempt AttemptInfo)
+
+	// OnAttemptEnd is called when the callback returns err, after running for duration
+	OnAttemptEnd(attempt AttemptInfo, err error, duration time.Duration)
+
+	// OnWait is called after a retryable error, just before the loop waits for wait before trying again.
+	// attempt is the attempt that failed
+	OnWait(attempt AttemptInfo, wait time.Duration)
+
+	// OnGiveUp is called when the loop stops without succeeding. err is the error returned by the loop.
+	// attempt is the last attempt made, its Number is 0 if no attempts were made
+	OnGiveUp(attempt AttemptInfo, reason GiveUpReason, err error)
+
+	// OnSuccess is called when attempt succeeds
+	OnSuccess(attempt AttemptInfo)
+}
+
+// GiveUpReason is why the loop stopped without succeeding
+type GiveUpReason int
+
+const (
+	// GiveUpNotRetryable means the callback returned an error that was not retryable
+	GiveUpNotRetryable GiveUpReason = iota
+	// GiveUpAttemptsExhausted means the callback returned a retryable error, but no more attempts were allowed
+	GiveUpAttemptsExhausted
+	// GiveUpContextDone means the context expired or was canceled
+	GiveUpContextDone
+	// GiveUpBudgetExhausted means the callback returned a retryable error, but Options.Budget did not allow a retry
+	GiveUpBudgetExhausted
+	// GiveUpDeadlineTooClose means the callback returned a retryable error, but Options.DeadlineAware found that
+	// another attempt would not finish before the context's deadline
+	GiveUpDeadlineTooClose
+)
+
+func (r GiveUpReason) String() string {
+	switch r {
+	case GiveUpNotRetryable:
+		return "not_retryable"
+	case GiveUpAttemptsExhausted:
+		return "attempts_exhausted"
+	case GiveUpContextDone:
+		return "context_done"
+	case GiveUpBudgetExhausted:
+		return "budget_exhausted"
+	case GiveUpDeadlineTooClose:
+		return "deadline_too_close"
+	default:
+		return "unknown"
+	}
+}
+
+// Observers notifies each of its observers in order
+type Observers []Observer
+
+func (o Observers) OnAttemptStart(attempt AttemptInfo) {
+	for _, observer := range o {
+		observer.OnAttemptStart(attempt)
+	}
+}
+
+func (o Observers) OnAttemptEnd(attempt AttemptInfo, err error, duration time.Duration) {
+	for _, observer := range o {
+		observer.OnAttemptEnd(attempt, err, duration)
+	}
+}
+
+func (o Observers) OnWait(attempt AttemptInfo, wait time.Duration) {
+	for _, observer := range o {
+		observer.OnWait(attempt, wait)
+	}
+}
+
+func (o Observers) OnGiveUp(attempt AttemptInfo, reason GiveUpReason, err error) {
+	for _, observer := range o {
+		observer.OnGiveUp(attempt, reason, err)
+	}
+}
+
+func (o Observers) OnSuccess(attempt AttemptInfo) {
+	for _, observer := range o {
+		observer.OnSuccess(attempt)
+	}
+}
+
+// NopObserver ignores everything. Embed it in your own Observer to only implement the methods you need
+type NopObserver struct{}
+
+func (NopObserver) OnAttemptStart(_ AttemptInfo) {}
+
+func (NopObserver) OnAttemptEnd(_ AttemptInfo, _ error, _ time.Duration) {}
+
+func (NopObserver) OnWait(_ AttemptInfo, _ time.Duration) {}
+
+func (NopObserver) OnGiveUp(_ AttemptInfo, _ GiveUpReason, _ error) {}
+
+func (NopObserver) OnSuccess(_ AttemptInfo) {}
@@ -0,0 +1,33 @@
+package retryLoop
+
+import (
+	"context"
+)
+
+// observerKey is the context key for the Observer added by WithObserver
+type observerKey struct{}
+
+// WithObserver returns a copy of ctx that makes any loop given it also notify observer, in addition to
+// Options.Observer. This lets you observe a retry without knowing which strategy runs it.
+// Observers added to the same context are all notified, in the order they were added.
+// The contexts given to attempts hide the observer, so retries nested inside an attempt are not reported to it
+func WithObserver(ctx context.Context, observer Observer) context.Context {
+	if existing := ObserverFrom(ctx); existing != nil {
+		observer = Observers{existing, observer}
+	}
+	return context.WithValue(ctx, observerKey{}, observer)
+}
+
+// ObserverFrom returns the observer added to ctx by WithObserver, or nil if there isn't one
+func ObserverFrom(ctx context.Context) Observer {
+	observer, _ := ctx.Value(observerKey{}).(Observer)
+	return observer
+}
+
+// hideObserver returns a copy of ctx without the observer added by WithObserver
+func hideObserver(ctx context.Context) context.Context {
+	if ObserverFrom(ctx) == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, observerKey{}, nil)
+}
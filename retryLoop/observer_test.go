@@ -0,0 +1,200 @@
+package retryLoop_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("Observer", func() {
+	var (
+		ctx      context.Context
+		cancel   context.CancelFunc
+		observer *retryMocks.Observer
+		options  retryLoop.Options
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+		observer = &retryMocks.Observer{}
+		options = retryLoop.Options{Observer: observer}
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	When("succeeds after a retry", func() {
+		It("notifies each step", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), func(_ uint64) time.Duration {
+				return 1 * time.Millisecond
+			}, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(observer.Events()).Should(Equal([]string{
+				"start 1",
+				"end 1 forced retry",
+				"wait 1",
+				"start 2",
+				"end 2",
+				"success 2",
+			}))
+			Expect(observer.Waits()).Should(Equal([]time.Duration{1 * time.Millisecond}))
+		})
+	})
+	When("an attempt ends", func() {
+		It("is given the attempt's error and duration", func() {
+			clock := retryMocks.NewFakeClock(time.Now())
+			var waited, started retryLoop.AttemptInfo
+			options.Observer = observerFuncs{
+				onAttemptStart: func(attempt retryLoop.AttemptInfo) {
+					started = attempt
+				},
+				onWait: func(attempt retryLoop.AttemptInfo, _ time.Duration) {
+					waited = attempt
+				},
+			}
+			options.Clock = clock
+			var given []retryLoop.AttemptInfo
+			_ = retryLoop.UntilAttempt(ctx, func(_ context.Context, attempt retryLoop.AttemptInfo) error {
+				given = append(given, attempt)
+				clock.Advance(3 * time.Second)
+				return retryMocks.ErrRetry
+			}, neverDelays, func(timesAttempted uint64) bool {
+				return timesAttempted < 2
+			}, options)
+			Expect(waited.Number).Should(Equal(uint64(1)))
+			Expect(waited.Err).Should(Equal(retryMocks.ErrRetryReason))
+			Expect(waited.Duration).Should(Equal(3 * time.Second))
+			Expect(started.Err).Should(BeNil())
+			Expect(started.Duration).Should(BeZero())
+			Expect(given[1].Err).Should(BeNil())
+			Expect(given[1].PreviousErr).Should(Equal(retryMocks.ErrRetryReason))
+		})
+	})
+	When("the loop is given a wait function", func() {
+		It("reports how long the wait took", func() {
+			clock := retryMocks.NewFakeClock(time.Now())
+			options.Clock = clock
+			var started []retryLoop.AttemptInfo
+			options.Observer = retryLoop.Observers{observer, observerFuncs{
+				onAttemptStart: func(attempt retryLoop.AttemptInfo) {
+					started = append(started, attempt)
+				},
+			}}
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := retryLoop.UntilWithOptions(ctx, mock.Generator(), func(_ uint64) {
+				clock.Advance(20 * time.Millisecond)
+			}, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(observer.Waits()).Should(Equal([]time.Duration{20 * time.Millisecond, 20 * time.Millisecond}))
+			Expect(started[1].Waited).Should(Equal(20 * time.Millisecond))
+		})
+		It("reports it to observers added to the context by Until", func() {
+			ctx = retryLoop.WithObserver(ctx, observer)
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+				retryError.StopSuccess,
+			}}
+			err := retryLoop.Until(ctx, mock.Generator(), func(_ uint64) {
+				time.Sleep(20 * time.Millisecond)
+			}, loopForever)
+			Expect(err).Should(BeNil())
+			Expect(observer.Waits()).Should(HaveLen(1))
+			Expect(observer.Waits()[0]).Should(BeNumerically(">=", 20*time.Millisecond))
+		})
+	})
+	When("retries exhausted", func() {
+		It("gives up", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrRetry,
+			}}
+			_ = retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopNever, options)
+			Expect(observer.Events()).Should(Equal([]string{
+				"start 1",
+				"end 1 forced retry",
+				"give up attempts_exhausted",
+			}))
+		})
+	})
+	When("error is not retryable", func() {
+		It("gives up", func() {
+			mock := &retryMocks.Callback{Responses: []error{
+				retryMocks.ErrThatCannotBeRetried,
+			}}
+			_ = retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(observer.Events()).Should(ContainElement("give up not_retryable"))
+		})
+	})
+	When("context is done", func() {
+		It("gives up without attempting", func() {
+			cancel()
+			mock := &retryMocks.Callback{}
+			_ = retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(observer.Events()).Should(Equal([]string{
+				"give up context_done",
+			}))
+		})
+	})
+	When("there are several observers", func() {
+		It("notifies all of them", func() {
+			other := &retryMocks.Observer{}
+			options.Observer = retryLoop.Observers{observer, other}
+			_ = retryLoop.UntilAttempt(ctx, retryLoop.IgnoreAttempt(retryMocks.AlwaysSucceeds), neverDelays, loopForever, options)
+			Expect(observer.Events()).Should(Equal([]string{"start 1", "end 1", "success 1"}))
+			Expect(other.Events()).Should(Equal(observer.Events()))
+		})
+	})
+	When("an observer is added to the context", func() {
+		var (
+			fromContext *retryMocks.Observer
+		)
+		BeforeEach(func() {
+			fromContext = &retryMocks.Observer{}
+			ctx = retryLoop.WithObserver(ctx, fromContext)
+		})
+		It("notifies it as well as the options' observer", func() {
+			_ = retryLoop.UntilAttempt(ctx, retryLoop.IgnoreAttempt(retryMocks.AlwaysSucceeds), neverDelays, loopForever, options)
+			Expect(fromContext.Events()).Should(Equal([]string{"start 1", "end 1", "success 1"}))
+			Expect(observer.Events()).Should(Equal(fromContext.Events()))
+		})
+		It("notifies it without an options' observer", func() {
+			_ = retryLoop.UntilAttempt(ctx, retryLoop.IgnoreAttempt(retryMocks.AlwaysSucceeds), neverDelays, loopForever, retryLoop.Options{})
+			Expect(fromContext.Events()).Should(Equal([]string{"start 1", "end 1", "success 1"}))
+		})
+		It("does not report retries nested in an attempt", func() {
+			_ = retryLoop.UntilAttempt(ctx, func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+				Expect(retryLoop.ObserverFrom(ctx)).Should(BeNil())
+				return retryLoop.UntilAttempt(ctx, retryLoop.IgnoreAttempt(retryMocks.AlwaysSucceeds), neverDelays, loopForever, retryLoop.Options{})
+			}, neverDelays, loopForever, retryLoop.Options{})
+			Expect(fromContext.Events()).Should(Equal([]string{"start 1", "end 1", "success 1"}))
+		})
+	})
+})
+
+// observerFuncs calls the functions that are set
+type observerFuncs struct {
+	retryLoop.NopObserver
+	onAttemptStart func(attempt retryLoop.AttemptInfo)
+	onWait         func(attempt retryLoop.AttemptInfo, wait time.Duration)
+}
+
+func (o observerFuncs) OnAttemptStart(attempt retryLoop.AttemptInfo) {
+	if o.onAttemptStart != nil {
+		o.onAttemptStart(attempt)
+	}
+}
+
+func (o observerFuncs) OnWait(attempt retryLoop.AttemptInfo, wait time.Duration) {
+	if o.onWait != nil {
+		o.onWait(attempt, wait)
+	}
+}
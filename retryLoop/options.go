@@ -0,0 +1,69 @@
+package retryLoop
+
+import (
+	"context"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"github.com/wojnosystems/go-retry/retryTrace"
+	"time"
+)
+
+// Options tune how UntilAttempt makes each attempt. The zero value is the same behavior as Until
+type Options struct {
+	// PerAttemptTimeout, if greater than 0, limits how long the context given to each attempt lives.
+	// The attempt's context will never outlive the context given to the loop.
+	// If an attempt returns an error after its own timeout expired, the error is retried as if it were wrapped in
+	// retryError.Again. If the loop's context is also done, the error is returned as-is and no more attempts are made
+	PerAttemptTimeout time.Duration
+
+	// MaxRetryAfter, if greater than 0, caps the waits hinted by errors made with retryError.AgainAfter or that
+	// implement retryError.RetryAfterHint. Without it, hints are only limited by the context
+	MaxRetryAfter time.Duration
+
+	// KeepHistory, if true, returns a *retryError.Exhausted containing every failed attempt whenever the loop fails
+	// after making at least one attempt, even if it was the only one. errors.Is and errors.As still match the error that
+	// would have been returned without the history, as well as the error of every attempt
+	KeepHistory bool
+
+	// Observer, if not nil, is notified of each attempt, wait, success and give up. Use Observers to notify several.
+	// Observers added to the loop's context with WithObserver are notified too
+	Observer Observer
+
+	// Clock, if not nil, is used to tell the time and to wait between attempts instead of the system clock.
+	// Contexts, including the PerAttemptTimeout, always use the system clock
+	Clock retrySleep.Clock
+
+	// Budget, if not nil, is consulted before each retry. If it does not allow the retry, the loop stops and returns
+	// the last error marked with retryError.ErrBudgetExhausted
+	Budget Budget
+
+	// Classifier, if not nil, decides whether errors that are not wrapped with retryError.Again should be retried.
+	// It is only consulted while the loop's context is alive. See retryClassify for the built-in classifiers
+	Classifier retryClassify.Classifier
+
+	// DeadlineAware, if true, stops retrying early when the loop's context has a deadline and the time left is less
+	// than the next wait plus how long an attempt is expected to take, which is the 90th percentile of the attempts
+	// made so far by this loop. The last error is returned marked with retryError.ErrDeadlineTooClose, instead of
+	// making an attempt that would most likely be cut short by the deadline
+	DeadlineAware bool
+
+	// Tracer, if not nil, makes each attempt inside its own span, a child of the span in the loop's context. The
+	// attempt's context contains the span. See retryTrace for the attributes set on it
+	Tracer retryTrace.Tracer
+}
+
+// observerFor returns the Observer to notify for a loop given ctx, which is never nil. It combines Observer with the
+// observer added to ctx by WithObserver
+func (o Options) observerFor(ctx context.Context) Observer {
+	fromContext := ObserverFrom(ctx)
+	switch {
+	case o.Observer == nil && fromContext == nil:
+		return NopObserver{}
+	case fromContext == nil:
+		return o.Observer
+	case o.Observer == nil:
+		return fromContext
+	default:
+		return Observers{o.Observer, fromContext}
+	}
+}
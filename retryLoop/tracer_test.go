@@ -0,0 +1,105 @@
+package retryLoop_test
+
+import (
+	"context"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"github.com/wojnosystems/go-retry/retryTrace"
+	"time"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		recorder *retryTrace.Recorder
+		options  retryLoop.Options
+	)
+	BeforeEach(func() {
+		recorder = retryTrace.NewRecorder()
+		options = retryLoop.Options{Tracer: recorder}
+	})
+
+	It("makes each attempt a child span of the retry's span", func() {
+		ctx, parent := recorder.Start(context.Background(), "request")
+		mock := &retryMocks.Callback{Responses: []error{retryMocks.ErrRetry, retryError.StopSuccess}}
+		err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), func(_ uint64) time.Duration {
+			return 20 * time.Millisecond
+		}, loopForever, options)
+		parent.End(err)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		attempts := recorder.Children(1)
+		Expect(attempts).Should(Equal([]retryTrace.RecordedSpan{
+			{
+				ID:       2,
+				ParentID: 1,
+				Name:     retryTrace.AttemptSpanName,
+				Attributes: map[string]interface{}{
+					retryTrace.AttemptKey: int64(1),
+					retryTrace.BackoffKey: 0.0,
+					retryTrace.OutcomeKey: retryTrace.OutcomeRetry,
+				},
+				Ended: true,
+				Err:   retryMocks.ErrRetryReason,
+			},
+			{
+				ID:       3,
+				ParentID: 1,
+				Name:     retryTrace.AttemptSpanName,
+				Attributes: map[string]interface{}{
+					retryTrace.AttemptKey: int64(2),
+					retryTrace.BackoffKey: 0.02,
+					retryTrace.OutcomeKey: retryTrace.OutcomeSuccess,
+				},
+				Ended: true,
+			},
+		}))
+	})
+
+	It("gives the attempt's span to the callback", func() {
+		err := retryLoop.UntilAttempt(context.Background(), func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+			_, child := recorder.Start(ctx, "query")
+			child.End(nil)
+			return retryError.StopSuccess
+		}, neverDelays, loopForever, options)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(recorder.Children(1)).Should(HaveLen(1))
+		Expect(recorder.Children(1)[0].Name).Should(Equal("query"))
+	})
+
+	It("records how the error was classified", func() {
+		errTransient := errors.New("transient")
+		options.Classifier = func(err error) retryClassify.Decision {
+			if err == errTransient {
+				return retryClassify.Retry
+			}
+			return retryClassify.Stop
+		}
+		mock := &retryMocks.Callback{Responses: []error{errTransient, retryMocks.ErrThatCannotBeRetried}}
+		_ = retryLoop.UntilAttempt(context.Background(), mock.AttemptGenerator(), neverDelays, loopForever, options)
+		spans := recorder.Spans()
+		Expect(spans).Should(HaveLen(2))
+		Expect(spans[0].Attributes).Should(HaveKeyWithValue(retryTrace.ClassifiedKey, retryTrace.OutcomeRetry))
+		Expect(spans[0].Attributes).Should(HaveKeyWithValue(retryTrace.OutcomeKey, retryTrace.OutcomeRetry))
+		Expect(spans[1].Attributes).Should(HaveKeyWithValue(retryTrace.ClassifiedKey, retryTrace.OutcomeStop))
+		Expect(spans[1].Attributes).Should(HaveKeyWithValue(retryTrace.OutcomeKey, retryTrace.OutcomeStop))
+		Expect(spans[1].Err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+	})
+
+	It("ends the attempt's span if the callback panics", func() {
+		Expect(func() {
+			_ = retryLoop.CallAttempt(context.Background(), func(_ context.Context, _ retryLoop.AttemptInfo) error {
+				panic("boom")
+			}, retryLoop.AttemptInfo{Number: 1}, options)
+		}).Should(PanicWith("boom"))
+		spans := recorder.Spans()
+		Expect(spans).Should(HaveLen(1))
+		Expect(spans[0].Ended).Should(BeTrue())
+		Expect(spans[0].Attributes).Should(HaveKeyWithValue(retryTrace.OutcomeKey, retryTrace.OutcomeStop))
+		Expect(spans[0].Err).Should(MatchError("panic: boom"))
+	})
+})
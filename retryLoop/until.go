@@ -2,8 +2,14 @@ package retryLoop
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/wojnosystems/go-retry/retryClassify"
 	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retrySleep"
+	"github.com/wojnosystems/go-retry/retryTrace"
 	"math"
+	"time"
 )
 
 // Until will continuously call the callback until shouldContinueLooping returns false.
@@ -13,45 +19,290 @@ import (
 // waits will be allowed to occur, or complete. That way, you should never over-wait the ctx deadline by a significant amount.
 // Wait may still be called after a context expires, wait is expected to take the context into account and only sleep
 // until the deadline expires or the retry wait duration expires, whichever occurs first.
+// If the retryable error hints how long to wait, see retryError.AgainAfter, the loop waits for the hint instead of calling wait.
+// An error is retryable if retryError.IsAgain is true for it, which also finds errors wrapped in Again further down
+// the error chain and errors that implement retryError.Retryable.
+// The Again wrapper is removed from errors returned and given to the next attempt only if it is the outermost error,
+// see retryError.UnwrapAgain.
+// The error returned tells you why the loop stopped:
+//   - a non-retryable error from the callback is returned as-is
+//   - if no more attempts are allowed, the last error is returned wrapped so that errors.Is matches both
+//     retryError.ErrAttemptsExhausted and the last error
+//   - if ctx is done, its error is returned. If an attempt was retried, it is wrapped so that errors.Is also
+//     matches the last retryable error
+//
+// Use retryError.LastCause to get the last error from the wrapped errors.
 // This method is the base for all retry logic. Both Forever and UpTo are intended to depend on this.
 func Until(ctx context.Context,
 	callback CallbackFunc,
 	wait WaitBetweenAttemptsFunc,
 	shouldContinueLooping ShouldContinueLoopingFunc,
 ) (err error) {
+	return UntilWithOptions(ctx, callback, wait, shouldContinueLooping, Options{})
+}
+
+// UntilWithOptions is like Until, but options tune the loop as they do for UntilAttempt, such as adding an Observer.
+// wait does the waiting itself, so the loop times how long it took using options.Clock and reports that as the wait,
+// such as to the Observer's OnWait, which is called once wait has returned.
+// With options.DeadlineAware, the time left is checked once wait has returned, as how long it will take isn't known
+// ahead of time
+func UntilWithOptions(ctx context.Context,
+	callback CallbackFunc,
+	wait WaitBetweenAttemptsFunc,
+	shouldContinueLooping ShouldContinueLoopingFunc,
+	options Options,
+) (err error) {
+	return until(ctx, IgnoreAttempt(callback), nil, wait, shouldContinueLooping, options)
+}
 
+// UntilAttempt is like Until, but each attempt is given its own context and information about the attempt.
+// Instead of waiting itself, delay returns how long to wait and the loop waits until that time passes or ctx is done.
+// If the retryable error hints how long to wait, see retryError.AgainAfter, delay is not called for that wait.
+// options tune how each attempt is made, the zero value behaves like Until
+// If options.Budget does not allow a retry, the last error is returned wrapped so that errors.Is matches both
+// retryError.ErrBudgetExhausted and the last error
+// If options.DeadlineAware finds there is not enough time left for another attempt, the last error is returned
+// wrapped so that errors.Is matches both retryError.ErrDeadlineTooClose and the last error
+func UntilAttempt(ctx context.Context,
+	callback AttemptCallbackFunc,
+	delay DelayFunc,
+	shouldContinueLooping ShouldContinueLoopingFunc,
+	options Options,
+) (err error) {
+	return until(ctx, callback, delay, nil, shouldContinueLooping, options)
+}
+
+// until is the loop behind Until and UntilAttempt. Only one of delay and wait is given: the loop waits for as long as
+// delay returns, while wait does the waiting itself
+func until(ctx context.Context,
+	callback AttemptCallbackFunc,
+	delay DelayFunc,
+	wait WaitBetweenAttemptsFunc,
+	shouldContinueLooping ShouldContinueLoopingFunc,
+	options Options,
+) (err error) {
+	clock := retrySleep.OrSystem(options.Clock)
+	startedAt := clock.Now()
+	observer := options.observerFor(ctx)
+	attempt := AttemptInfo{}
 	timesAttempted := uint64(0)
+	var attempts *history
+	if options.KeepHistory {
+		attempts = &history{}
+	}
+	var durations *latencies
+	if options.DeadlineAware {
+		durations = &latencies{}
+	}
+	giveUp := func(reason GiveUpReason, err error) error {
+		if attempts != nil {
+			err = attempts.exhausted(err)
+		}
+		observer.OnGiveUp(attempt, reason, err)
+		return err
+	}
+	if options.Budget != nil {
+		options.Budget.Requested()
+	}
 	for {
 		// Check if context is done, if not, continue
 		select {
 		case <-ctx.Done():
 			// ctx expired or was cancelled, we're done
-			return ctx.Err()
+			if attempt.PreviousErr != nil {
+				return giveUp(GiveUpContextDone, retryError.ContextDone(ctx.Err(), attempt.PreviousErr))
+			}
+			return giveUp(GiveUpContextDone, ctx.Err())
 		default:
 			// fall-through, ctx is not done
 		}
+		attempt.Number = timesAttempted
+		if attempt.Number != math.MaxUint64 {
+			attempt.Number++
+		}
+		attemptStartedAt := clock.Now()
+		attempt.Elapsed = attemptStartedAt.Sub(startedAt)
+		attempt.Err = nil
+		attempt.Duration = 0
 		// call the callback, record the response
-		err = callback()
+		observer.OnAttemptStart(attempt)
+		err = CallAttempt(ctx, callback, attempt, options)
+		attempt.Err = retryError.UnwrapAgain(err)
+		attempt.Duration = clock.Now().Sub(attemptStartedAt)
+		observer.OnAttemptEnd(attempt, err, attempt.Duration)
+		if durations != nil {
+			durations.add(attempt.Duration)
+		}
 		if err == retryError.StopSuccess {
 			// attempt succeeded, no need to wait or try again
+			observer.OnSuccess(attempt)
 			return
 		}
-		if v, ok := err.(retryError.AgainWrapper); !ok {
+		if attempts != nil {
+			attempts.failed(attemptStartedAt, err)
+		}
+		if !retryError.IsAgain(err) {
 			// error was no retryable, stop retrying without waiting
-			return err
+			if attempt.PreviousErr != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				// the attempt failed because ctx is done, keep the error that caused us to retry
+				return giveUp(GiveUpContextDone, retryError.ContextDone(err, attempt.PreviousErr))
+			}
+			return giveUp(GiveUpNotRetryable, err)
 		} else {
-			// error was retryable
+			// error was retryable, pass on what it wrapped
+			cause := retryError.UnwrapAgain(err)
 			if timesAttempted != math.MaxUint64 {
 				// only count up if that's possible, avoid overflow
 				timesAttempted++
 			}
-			if shouldContinueLooping(timesAttempted) {
-				// we should continue looping, so wait before trying again
+			if !shouldContinueLooping(timesAttempted) {
+				// we should not loop again, return the last error we got, without the retryAgain wrapper, marked as exhausted
+				return giveUp(GiveUpAttemptsExhausted, retryError.AttemptsExhausted(cause))
+			}
+			// we should continue looping, so work out how long to wait before trying again
+			waitStartedAt := clock.Now()
+			sleepTime, hinted := retryAfter(err, options)
+			waitedAlready := !hinted && wait != nil
+			if waitedAlready {
 				wait(timesAttempted - 1)
-			} else {
-				// we should not loop again, just return the last error we got, without the retryAgain wrapper
-				return v.Unwrap()
+				sleepTime = clock.Now().Sub(waitStartedAt)
+			} else if !hinted {
+				sleepTime = delay(timesAttempted - 1)
+			}
+			waitLeft := sleepTime
+			if waitedAlready {
+				waitLeft = 0
+			}
+			if durations != nil && !fitsBeforeDeadline(ctx, waitLeft+durations.percentile(expectedLatencyPercentile)) {
+				// the next attempt would most likely be cut short by the deadline, don't waste the wait and the call
+				return giveUp(GiveUpDeadlineTooClose, retryError.DeadlineTooClose(cause))
+			}
+			if options.Budget != nil && !options.Budget.Withdraw() {
+				// too many retries are being made, give up rather than add to the load
+				return giveUp(GiveUpBudgetExhausted, retryError.BudgetExhausted(cause))
+			}
+			observer.OnWait(attempt, sleepTime)
+			if sleepTime > 0 && !waitedAlready {
+				retrySleep.WithClock(ctx, clock, sleepTime)
 			}
+			if attempts != nil {
+				attempts.waited(clock.Now().Sub(waitStartedAt))
+			}
+			attempt.PreviousErr = cause
+			attempt.Waited = sleepTime
 		}
 	}
 }
+
+// CallAttempt calls the callback with a context that only lives as long as the attempt, limited by
+// options.PerAttemptTimeout. If the attempt failed because its own context timed out, but ctx is still alive, the
+// error is made retryable. Otherwise, errors that are not retryable are given to options.Classifier, if there is one.
+// If options.Tracer is set, the attempt is made inside its own span, which is ended even if the callback panics.
+// Use it to make attempts the same way the loop does
+func CallAttempt(ctx context.Context, callback AttemptCallbackFunc, attempt AttemptInfo, options Options) (err error) {
+	if options.Tracer == nil {
+		_, err = callAttempt(ctx, callback, attempt, options)
+		return
+	}
+	ctx, span := options.Tracer.Start(ctx, retryTrace.AttemptSpanName)
+	span.SetAttributes(
+		retryTrace.Int64(retryTrace.AttemptKey, int64(attempt.Number)),
+		retryTrace.Float64(retryTrace.BackoffKey, attempt.Waited.Seconds()),
+	)
+	var decision *retryClassify.Decision
+	defer func() {
+		endSpan(span, err, decision, recover())
+	}()
+	decision, err = callAttempt(ctx, callback, attempt, options)
+	return
+}
+
+// callAttempt makes the attempt for CallAttempt. decision is what the Classifier decided, nil if it was not consulted
+func callAttempt(ctx context.Context, callback AttemptCallbackFunc, attempt AttemptInfo, options Options) (decision *retryClassify.Decision, err error) {
+	var attemptCtx context.Context
+	var cancel context.CancelFunc
+	if options.PerAttemptTimeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, options.PerAttemptTimeout)
+	} else {
+		attemptCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+	err = callback(hideObserver(attemptCtx), attempt)
+	if err == nil || retryError.IsAgain(err) {
+		return
+	}
+	if attemptTimedOut(ctx, attemptCtx) {
+		return nil, retryError.Again(err)
+	}
+	if options.Classifier != nil && ctx.Err() == nil {
+		classification := options.Classifier(err)
+		return &classification, classified(err, classification)
+	}
+	return
+}
+
+// endSpan records the outcome of an attempt on its span and ends it. recovered is what recover returned, if the
+// callback panicked, the span ends with the panic and the panic carries on
+func endSpan(span retryTrace.Span, err error, decision *retryClassify.Decision, recovered interface{}) {
+	if recovered != nil {
+		span.SetAttributes(retryTrace.String(retryTrace.OutcomeKey, retryTrace.OutcomeStop))
+		span.End(fmt.Errorf("panic: %v", recovered))
+		panic(recovered)
+	}
+	outcome := retryTrace.OutcomeStop
+	if err == nil {
+		outcome = retryTrace.OutcomeSuccess
+	} else if retryError.IsAgain(err) {
+		outcome = retryTrace.OutcomeRetry
+	}
+	span.SetAttributes(retryTrace.String(retryTrace.OutcomeKey, outcome))
+	if decision != nil {
+		classifiedAs := retryTrace.OutcomeStop
+		if decision.Retry {
+			classifiedAs = retryTrace.OutcomeRetry
+		}
+		span.SetAttributes(retryTrace.String(retryTrace.ClassifiedKey, classifiedAs))
+	}
+	span.End(retryError.UnwrapAgain(err))
+}
+
+// classified marks err as retryable if the decision is to retry it
+func classified(err error, decision retryClassify.Decision) error {
+	switch {
+	case !decision.Retry:
+		return err
+	case decision.After > 0:
+		return retryError.AgainAfter(err, decision.After)
+	default:
+		return retryError.Again(err)
+	}
+}
+
+// fitsBeforeDeadline is true if ctx has no deadline, or if needed is no longer than the time left before it.
+// Like contexts, this uses the system clock
+func fitsBeforeDeadline(ctx context.Context, needed time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || needed <= time.Until(deadline)
+}
+
+// attemptTimedOut is true if only the attempt's deadline has passed. If ctx is also done, the loop is over
+func attemptTimedOut(ctx, attemptCtx context.Context) bool {
+	return attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+}
+
+// retryAfter returns the wait hinted by err, capped by options.MaxRetryAfter. The strategy's delay is not consulted
+// when there is a hint
+func retryAfter(err error, options Options) (wait time.Duration, ok bool) {
+	wait, ok = retryError.RetryAfter(err)
+	if ok && options.MaxRetryAfter > 0 && wait > options.MaxRetryAfter {
+		wait = options.MaxRetryAfter
+	}
+	return
+}
+
+// IgnoreAttempt adapts a CallbackFunc so that it can be used where an AttemptCallbackFunc is expected
+func IgnoreAttempt(callback CallbackFunc) AttemptCallbackFunc {
+	return func(_ context.Context, _ AttemptInfo) error {
+		return callback()
+	}
+}
@@ -0,0 +1,409 @@
+package retryLoop_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryClassify"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+func neverDelays(_ uint64) time.Duration {
+	return 0
+}
+
+var _ = Describe("UntilAttempt", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	When("retries twice", func() {
+		var (
+			mock *retryMocks.Callback
+		)
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					retryMocks.ErrRetry,
+					retryMocks.ErrRetry,
+					retryError.StopSuccess,
+				},
+			}
+		})
+		It("numbers each attempt", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, retryLoop.Options{})
+			Expect(err).Should(BeNil())
+			Expect(mock.Attempts()).Should(HaveLen(3))
+			for i, attempt := range mock.Attempts() {
+				Expect(attempt.Number).Should(BeNumerically("==", i+1))
+			}
+		})
+		It("passes the previous error", func() {
+			_ = retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, retryLoop.Options{})
+			Expect(mock.Attempts()[0].PreviousErr).Should(BeNil())
+			Expect(mock.Attempts()[1].PreviousErr).Should(Equal(retryMocks.ErrRetryReason))
+		})
+		It("waits for the delay", func() {
+			elapsed := retryMocks.DurationElapsed(func() {
+				_ = retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), func(_ uint64) time.Duration {
+					return 5 * time.Millisecond
+				}, loopForever, retryLoop.Options{})
+			})
+			Expect(elapsed).Should(BeNumerically(">=", 10*time.Millisecond))
+			Expect(mock.Attempts()[2].Elapsed).Should(BeNumerically(">=", 10*time.Millisecond))
+		})
+	})
+	When("the attempt context is used", func() {
+		It("is canceled after the attempt", func() {
+			var attemptCtx context.Context
+			err := retryLoop.UntilAttempt(ctx, func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+				attemptCtx = ctx
+				return nil
+			}, neverDelays, loopForever, retryLoop.Options{})
+			Expect(err).Should(BeNil())
+			Expect(attemptCtx.Err()).Should(Equal(context.Canceled))
+			Expect(ctx.Err()).Should(BeNil())
+		})
+	})
+	When("an attempt exceeds the per-attempt timeout", func() {
+		var (
+			timesRun int
+			callback retryLoop.AttemptCallbackFunc
+		)
+		BeforeEach(func() {
+			timesRun = 0
+			callback = func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+				timesRun++
+				if timesRun == 1 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return retryError.StopSuccess
+			}
+		})
+		It("retries the attempt", func() {
+			err := retryLoop.UntilAttempt(ctx, callback, neverDelays, loopForever, retryLoop.Options{PerAttemptTimeout: 5 * time.Millisecond})
+			Expect(err).Should(BeNil())
+			Expect(timesRun).Should(Equal(2))
+		})
+		It("returns the timeout when out of attempts", func() {
+			err := retryLoop.UntilAttempt(ctx, callback, neverDelays, loopNever, retryLoop.Options{PerAttemptTimeout: 5 * time.Millisecond})
+			Expect(err).Should(MatchError(context.DeadlineExceeded))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(timesRun).Should(Equal(1))
+		})
+		It("does not retry once the loop's context expires", func() {
+			shortCtx, shortCancel := context.WithTimeout(ctx, 5*time.Millisecond)
+			defer shortCancel()
+			err := retryLoop.UntilAttempt(shortCtx, callback, neverDelays, loopForever, retryLoop.Options{PerAttemptTimeout: 1 * time.Second})
+			Expect(err).Should(Equal(context.DeadlineExceeded))
+			Expect(timesRun).Should(Equal(1))
+		})
+	})
+	When("the error hints how long to wait", func() {
+		var (
+			mock *retryMocks.Callback
+		)
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					retryError.AgainAfter(retryMocks.ErrRetryReason, 1*time.Millisecond),
+					retryError.StopSuccess,
+				},
+			}
+		})
+		It("waits for the hint instead of the delay", func() {
+			elapsed := retryMocks.DurationElapsed(func() {
+				err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), func(_ uint64) time.Duration {
+					return 1 * time.Hour
+				}, loopForever, retryLoop.Options{})
+				Expect(err).Should(BeNil())
+			})
+			Expect(elapsed).Should(BeNumerically("<", 500*time.Millisecond))
+		})
+		It("caps the hint", func() {
+			mock.Responses[0] = retryError.AgainAfter(retryMocks.ErrRetryReason, 1*time.Hour)
+			elapsed := retryMocks.DurationElapsed(func() {
+				err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, retryLoop.Options{
+					MaxRetryAfter: 1 * time.Millisecond,
+				})
+				Expect(err).Should(BeNil())
+			})
+			Expect(elapsed).Should(BeNumerically("<", 500*time.Millisecond))
+		})
+	})
+	When("keeping history", func() {
+		var (
+			mock    *retryMocks.Callback
+			options retryLoop.Options
+		)
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					retryMocks.ErrRetry,
+					retryMocks.ErrRetry,
+					retryMocks.ErrThatCannotBeRetried,
+				},
+			}
+			options = retryLoop.Options{KeepHistory: true}
+		})
+		It("returns every attempt", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), func(_ uint64) time.Duration {
+				return 1 * time.Millisecond
+			}, loopForever, options)
+			var exhausted *retryError.Exhausted
+			Expect(errors.As(err, &exhausted)).Should(BeTrue())
+			Expect(exhausted.Attempts).Should(HaveLen(3))
+			Expect(exhausted.Attempts[0].Err).Should(Equal(retryMocks.ErrRetryReason))
+			Expect(exhausted.Attempts[0].Wait).Should(BeNumerically(">=", 1*time.Millisecond))
+			Expect(exhausted.Attempts[2].Wait).Should(BeZero())
+			Expect(exhausted.Attempts[1].StartedAt).Should(BeTemporally(">", exhausted.Attempts[0].StartedAt))
+			Expect(exhausted.Err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+		})
+		It("matches every attempt's error", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(errors.Is(err, retryMocks.ErrRetryReason)).Should(BeTrue())
+			Expect(errors.Is(err, retryMocks.ErrThatCannotBeRetried)).Should(BeTrue())
+		})
+		It("keeps the history of a single attempt", func() {
+			mock.Responses = []error{retryMocks.ErrThatCannotBeRetried}
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			var exhausted *retryError.Exhausted
+			Expect(errors.As(err, &exhausted)).Should(BeTrue())
+			Expect(exhausted.Attempts).Should(HaveLen(1))
+			Expect(exhausted.Attempts[0].StartedAt).ShouldNot(BeZero())
+			Expect(err).Should(MatchError(retryMocks.ErrThatCannotBeRetried.Error() + " (after 1 attempt)"))
+		})
+		It("returns nil on success", func() {
+			mock.Responses[2] = retryError.StopSuccess
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(err).Should(BeNil())
+		})
+	})
+	When("limited by a budget", func() {
+		var (
+			mock     *retryMocks.Callback
+			budget   *retryMocks.Budget
+			observer *retryMocks.Observer
+			options  retryLoop.Options
+		)
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					retryMocks.ErrRetry,
+					retryMocks.ErrRetry,
+					retryMocks.ErrRetry,
+					retryError.StopSuccess,
+				},
+			}
+			budget = &retryMocks.Budget{Retries: 1}
+			observer = &retryMocks.Observer{}
+			options = retryLoop.Options{Budget: budget, Observer: observer}
+		})
+		It("stops when the budget is exhausted", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(err).Should(MatchError(retryError.ErrBudgetExhausted))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(mock.TimesRun()).Should(Equal(2))
+			Expect(observer.Events()).Should(ContainElement("give up budget_exhausted"))
+		})
+		It("requests once per loop", func() {
+			_ = retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(budget.Requests()).Should(Equal(uint(1)))
+			Expect(budget.Withdrawals()).Should(Equal(uint(2)))
+		})
+		It("does not withdraw when attempts are exhausted", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopNever, options)
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(budget.Withdrawals()).Should(BeZero())
+		})
+		It("does not withdraw on success", func() {
+			mock.Responses[0] = retryError.StopSuccess
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(budget.Withdrawals()).Should(BeZero())
+		})
+	})
+	When("deadline aware", func() {
+		var (
+			clock     *retryMocks.FakeClock
+			durations []time.Duration
+			observer  *retryMocks.Observer
+			options   retryLoop.Options
+			attempts  int
+			slowly    retryLoop.AttemptCallbackFunc
+		)
+		BeforeEach(func() {
+			clock = retryMocks.NewFakeClock(time.Now())
+			observer = &retryMocks.Observer{}
+			options = retryLoop.Options{DeadlineAware: true, Clock: clock, Observer: observer}
+			attempts = 0
+			// each attempt takes the next of durations on the fake clock, the last one repeats
+			slowly = func(_ context.Context, _ retryLoop.AttemptInfo) error {
+				duration := durations[len(durations)-1]
+				if attempts < len(durations) {
+					duration = durations[attempts]
+				}
+				attempts++
+				clock.Advance(duration)
+				if attempts == 5 {
+					return retryError.StopSuccess
+				}
+				return retryMocks.ErrRetry
+			}
+		})
+		It("stops before an attempt that would not finish in time", func() {
+			hourCtx, hourCancel := context.WithTimeout(context.Background(), time.Hour)
+			defer hourCancel()
+			durations = []time.Duration{time.Minute, time.Minute, 70 * time.Minute}
+			err := retryLoop.UntilAttempt(hourCtx, slowly, neverDelays, loopForever, options)
+			Expect(err).Should(MatchError(retryError.ErrDeadlineTooClose))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(attempts).Should(Equal(3))
+			Expect(observer.Events()).Should(ContainElement("give up deadline_too_close"))
+		})
+		It("stops Until before an attempt that would not finish in time", func() {
+			hourCtx, hourCancel := context.WithTimeout(context.Background(), time.Hour)
+			defer hourCancel()
+			durations = []time.Duration{time.Minute, time.Minute, 70 * time.Minute}
+			err := retryLoop.UntilWithOptions(hourCtx, func() error {
+				return slowly(hourCtx, retryLoop.AttemptInfo{})
+			}, retryMocks.NeverWaits, loopForever, options)
+			Expect(err).Should(MatchError(retryError.ErrDeadlineTooClose))
+			Expect(attempts).Should(Equal(3))
+		})
+		It("does not count a wait that already happened in Until", func() {
+			hourCtx, hourCancel := context.WithTimeout(context.Background(), time.Hour)
+			defer hourCancel()
+			durations = []time.Duration{time.Minute}
+			err := retryLoop.UntilWithOptions(hourCtx, func() error {
+				return slowly(hourCtx, retryLoop.AttemptInfo{})
+			}, func(_ uint64) {
+				clock.Advance(2 * time.Hour)
+			}, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(attempts).Should(Equal(5))
+		})
+		It("includes the next wait", func() {
+			hourCtx, hourCancel := context.WithTimeout(context.Background(), time.Hour)
+			defer hourCancel()
+			durations = []time.Duration{time.Minute}
+			err := retryLoop.UntilAttempt(hourCtx, slowly, func(_ uint64) time.Duration {
+				return 2 * time.Hour
+			}, loopForever, options)
+			Expect(err).Should(MatchError(retryError.ErrDeadlineTooClose))
+			Expect(attempts).Should(Equal(1))
+		})
+		It("keeps retrying while attempts fit", func() {
+			hourCtx, hourCancel := context.WithTimeout(context.Background(), time.Hour)
+			defer hourCancel()
+			durations = []time.Duration{time.Minute}
+			err := retryLoop.UntilAttempt(hourCtx, slowly, neverDelays, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(attempts).Should(Equal(5))
+		})
+		It("keeps retrying without a deadline", func() {
+			durations = []time.Duration{2 * time.Hour}
+			err := retryLoop.UntilAttempt(context.Background(), slowly, neverDelays, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(attempts).Should(Equal(5))
+		})
+		It("keeps retrying when not enabled", func() {
+			hourCtx, hourCancel := context.WithTimeout(context.Background(), time.Hour)
+			defer hourCancel()
+			durations = []time.Duration{2 * time.Hour}
+			options.DeadlineAware = false
+			err := retryLoop.UntilAttempt(hourCtx, slowly, neverDelays, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(attempts).Should(Equal(5))
+		})
+	})
+	When("classifying errors", func() {
+		var (
+			mock     *retryMocks.Callback
+			observer *retryMocks.Observer
+			options  retryLoop.Options
+		)
+		BeforeEach(func() {
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					retryMocks.ErrThatCannotBeRetried,
+					retryError.StopSuccess,
+				},
+			}
+			observer = &retryMocks.Observer{}
+			options = retryLoop.Options{Observer: observer, Classifier: func(err error) retryClassify.Decision {
+				if errors.Is(err, retryMocks.ErrThatCannotBeRetried) {
+					return retryClassify.RetryAfter(2 * time.Millisecond)
+				}
+				return retryClassify.Stop
+			}}
+		})
+		It("retries errors the classifier retries", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(err).Should(BeNil())
+			Expect(mock.TimesRun()).Should(Equal(2))
+			Expect(mock.Attempts()[1].PreviousErr).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+			Expect(observer.Waits()).Should(Equal([]time.Duration{2 * time.Millisecond}))
+		})
+		It("stops on errors the classifier stops", func() {
+			mock.Responses[0] = errors.New("other")
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, loopForever, options)
+			Expect(err).Should(MatchError("other"))
+			Expect(mock.TimesRun()).Should(Equal(1))
+		})
+		It("does not classify once the context is done", func() {
+			err := retryLoop.UntilAttempt(ctx, func(_ context.Context, _ retryLoop.AttemptInfo) error {
+				cancel()
+				return retryMocks.ErrThatCannotBeRetried
+			}, neverDelays, loopForever, options)
+			Expect(err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+		})
+	})
+	When("a retryable error is wrapped by middleware", func() {
+		var (
+			wrapped error
+			mock    *retryMocks.Callback
+		)
+		BeforeEach(func() {
+			wrapped = fmt.Errorf("middleware: %w", retryMocks.ErrRetry)
+			mock = &retryMocks.Callback{
+				Responses: []error{
+					wrapped,
+					wrapped,
+				},
+			}
+		})
+		It("is still retried", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, retryLoop.LoopUpTo(2), retryLoop.Options{})
+			Expect(mock.TimesRun()).Should(Equal(2))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+		})
+		It("keeps the wrapping in the errors it returns", func() {
+			err := retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, retryLoop.LoopUpTo(2), retryLoop.Options{})
+			Expect(mock.Attempts()[1].PreviousErr).Should(Equal(wrapped))
+			Expect(retryError.LastCause(err)).Should(Equal(wrapped))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+		})
+		It("is not retried by an outer loop once exhausted", func() {
+			outer := &retryMocks.Callback{Responses: []error{retryError.StopSuccess}}
+			err := retryLoop.UntilAttempt(ctx, func(ctx context.Context, attempt retryLoop.AttemptInfo) error {
+				_ = outer.AttemptGenerator()(ctx, attempt)
+				return retryLoop.UntilAttempt(ctx, mock.AttemptGenerator(), neverDelays, retryLoop.LoopUpTo(2), retryLoop.Options{})
+			}, neverDelays, loopForever, retryLoop.Options{})
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
+			Expect(outer.TimesRun()).Should(Equal(1))
+		})
+	})
+})
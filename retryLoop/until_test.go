@@ -98,7 +98,8 @@ var _ = Describe("Until", func() {
 			})
 			It("returns the last retry error", func() {
 				err := retryLoop.Until(ctx, mock.Generator(), retryMocks.NeverWaits, loopNever)
-				Expect(err).Should(Equal(retryMocks.ErrRetryReason))
+				Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+				Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
 				Expect(mock.TimesRun()).Should(Equal(1))
 			})
 		})
@@ -126,4 +127,39 @@ var _ = Describe("Until", func() {
 			Expect(mock.TimesRun()).Should(Equal(0))
 		})
 	})
+	When("context expires after retrying", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+		)
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+		})
+		AfterEach(func() {
+			cancel()
+		})
+		It("carries the last retryable error", func() {
+			err := retryLoop.Until(ctx, func() error {
+				return retryMocks.ErrRetry
+			}, func(_ uint64) {
+				cancel()
+			}, loopForever)
+			Expect(err).Should(MatchError(context.Canceled))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(retryError.LastCause(err)).Should(Equal(retryMocks.ErrRetryReason))
+		})
+		It("carries the last retryable error when the attempt fails because of the context", func() {
+			timesRun := 0
+			err := retryLoop.UntilAttempt(ctx, func(ctx context.Context, _ retryLoop.AttemptInfo) error {
+				timesRun++
+				if timesRun == 1 {
+					return retryMocks.ErrRetry
+				}
+				cancel()
+				return ctx.Err()
+			}, neverDelays, loopForever, retryLoop.Options{})
+			Expect(err).Should(MatchError(context.Canceled))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+		})
+	})
 })
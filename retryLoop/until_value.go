@@ -0,0 +1,27 @@
+package retryLoop
+
+import (
+	"context"
+)
+
+// UntilValue is Until for callbacks that produce a value. The value from the attempt that succeeded is returned.
+// callback is given the context for the attempt, see UntilAttempt.
+// If the loop fails, the zero value of T is returned along with the error
+func UntilValue[T any](ctx context.Context,
+	callback func(ctx context.Context) (value T, err error),
+	wait WaitBetweenAttemptsFunc,
+	shouldContinueLooping ShouldContinueLoopingFunc,
+) (value T, err error) {
+	err = until(ctx, func(ctx context.Context, _ AttemptInfo) error {
+		attemptValue, attemptErr := callback(ctx)
+		if attemptErr == nil {
+			value = attemptValue
+		}
+		return attemptErr
+	}, nil, wait, shouldContinueLooping, Options{})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
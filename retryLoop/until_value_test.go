@@ -0,0 +1,48 @@
+package retryLoop_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"time"
+)
+
+var _ = Describe("UntilValue", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 1*time.Second)
+	})
+	AfterEach(func() {
+		cancel()
+	})
+	When("retries once", func() {
+		It("returns the value of the successful attempt", func() {
+			mock := &retryMocks.Callback{
+				Responses: []error{
+					retryMocks.ErrRetry,
+					nil,
+				},
+			}
+			generator := mock.Generator()
+			value, err := retryLoop.UntilValue(ctx, func(_ context.Context) (int, error) {
+				return mock.TimesRun() + 1, generator()
+			}, retryMocks.NeverWaits, loopForever)
+			Expect(err).Should(BeNil())
+			Expect(value).Should(Equal(2))
+		})
+	})
+	When("unrecoverable error", func() {
+		It("returns the zero value", func() {
+			value, err := retryLoop.UntilValue(ctx, func(_ context.Context) (int, error) {
+				return 5, retryMocks.ErrThatCannotBeRetried
+			}, retryMocks.NeverWaits, loopForever)
+			Expect(err).Should(Equal(retryMocks.ErrThatCannotBeRetried))
+			Expect(value).Should(BeZero())
+		})
+	})
+})
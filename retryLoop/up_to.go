@@ -4,9 +4,14 @@ import (
 	"context"
 )
 
+// LoopUpTo returns a ShouldContinueLoopingFunc that stops looping once maxAttempts have been made
+func LoopUpTo(maxAttempts uint64) ShouldContinueLoopingFunc {
+	return func(timesAttempted uint64) bool {
+		return timesAttempted < maxAttempts
+	}
+}
+
 // UpTo will call callback until it returns a non-retryable error, success, or maxAttempts is exceeded
 func UpTo(ctx context.Context, callback CallbackFunc, wait WaitBetweenAttemptsFunc, maxAttempts uint64) (err error) {
-	return Until(ctx, callback, wait, func(timesAttempted uint64) bool {
-		return timesAttempted < maxAttempts
-	})
+	return Until(ctx, callback, wait, LoopUpTo(maxAttempts))
 }
@@ -58,7 +58,8 @@ var _ = Describe("UpTo", func() {
 		})
 		It("stops retrying", func() {
 			err := retryLoop.UpTo(ctx, mock.Generator(), retryMocks.NeverWaits, noRetries)
-			Expect(err).Should(Equal(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
 			Expect(mock.TimesRun()).Should(Equal(1))
 		})
 	})
@@ -94,7 +95,8 @@ var _ = Describe("UpTo", func() {
 		})
 		It("returns the last retry error", func() {
 			err := retryLoop.UpTo(ctx, mock.Generator(), retryMocks.NeverWaits, noRetries)
-			Expect(err).Should(Equal(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryMocks.ErrRetryReason))
+			Expect(err).Should(MatchError(retryError.ErrAttemptsExhausted))
 			Expect(mock.TimesRun()).Should(Equal(1))
 		})
 	})
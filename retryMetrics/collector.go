@@ -0,0 +1,166 @@
+package retryMetrics
+
+import (
+	"encoding/json"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Collector counts attempts, retries, successes and give ups, and records how long attempts and waits take, for each
+// named strategy. Get an Observer for each strategy with Observer and set it as the strategy's Observer, or add it to
+// the context with retryLoop.WithObserver.
+// Render the metrics with WritePrometheus or serve them as an http.Handler. A Collector is also an expvar.Var, so it
+// can be published with expvar.Publish.
+// It is safe for concurrent use. Recording never blocks, except the first time a name is used
+type Collector struct {
+	// Buckets are the upper bounds of the histogram buckets for attempt durations and waits. nil uses DefaultBuckets.
+	// Changing Buckets only affects strategies named after the change
+	Buckets []time.Duration
+
+	mu         sync.Mutex
+	strategies map[string]*series
+}
+
+// NewCollector creates a Collector that uses DefaultBuckets
+func NewCollector() *Collector {
+	return &Collector{
+		Buckets: DefaultBuckets,
+	}
+}
+
+// Stats is a snapshot of the metrics of one strategy
+type Stats struct {
+	// Attempts is how many attempts were started
+	Attempts uint64
+
+	// Retries is how many times the loop waited to try again
+	Retries uint64
+
+	// Successes is how many loops ended with a successful attempt
+	Successes uint64
+
+	// GiveUps is how many loops stopped without succeeding, by the String of the retryLoop.GiveUpReason
+	GiveUps map[string]uint64
+
+	// AttemptDurations is how long attempts took
+	AttemptDurations Histogram
+
+	// Waits is how long the loop waited before each retry
+	Waits Histogram
+}
+
+// Observer returns the retryLoop.Observer that records the metrics of the strategy called name.
+// Observers for the same name share their metrics
+func (c *Collector) Observer(name string) retryLoop.Observer {
+	return &observer{series: c.series(name)}
+}
+
+// Snapshot returns the metrics of every named strategy
+func (c *Collector) Snapshot() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := make(map[string]Stats, len(c.strategies))
+	for name, s := range c.strategies {
+		snapshot[name] = s.snapshot()
+	}
+	return snapshot
+}
+
+// String returns the Snapshot as JSON, with durations in seconds, so that a Collector is an expvar.Var
+func (c *Collector) String() string {
+	type histogramJSON struct {
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum_seconds"`
+		Buckets map[string]uint64 `json:"buckets"`
+	}
+	type statsJSON struct {
+		Attempts         uint64            `json:"attempts"`
+		Retries          uint64            `json:"retries"`
+		Successes        uint64            `json:"successes"`
+		GiveUps          map[string]uint64 `json:"give_ups"`
+		AttemptDurations histogramJSON     `json:"attempt_duration_seconds"`
+		Waits            histogramJSON     `json:"wait_seconds"`
+	}
+	toJSON := func(h Histogram) histogramJSON {
+		buckets := make(map[string]uint64, len(h.Buckets))
+		for _, bucket := range h.Buckets {
+			buckets[formatSeconds(bucket.UpperBound)] = bucket.Count
+		}
+		return histogramJSON{Count: h.Count, Sum: h.Sum.Seconds(), Buckets: buckets}
+	}
+	out := make(map[string]statsJSON)
+	for name, stats := range c.Snapshot() {
+		out[name] = statsJSON{
+			Attempts:         stats.Attempts,
+			Retries:          stats.Retries,
+			Successes:        stats.Successes,
+			GiveUps:          stats.GiveUps,
+			AttemptDurations: toJSON(stats.AttemptDurations),
+			Waits:            toJSON(stats.Waits),
+		}
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// series returns the metrics of the strategy called name, creating them the first time
+func (c *Collector) series(name string) *series {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.strategies[name]; ok {
+		return s
+	}
+	if c.strategies == nil {
+		c.strategies = make(map[string]*series)
+	}
+	bounds := c.Buckets
+	if bounds == nil {
+		bounds = DefaultBuckets
+	}
+	sorted := make([]time.Duration, len(bounds))
+	copy(sorted, bounds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	s := &series{
+		attemptDurations: newHistogram(sorted),
+		waits:            newHistogram(sorted),
+		giveUps:          make(map[string]uint64),
+	}
+	c.strategies[name] = s
+	return s
+}
+
+// series are the metrics of one strategy
+type series struct {
+	attempts         uint64
+	retries          uint64
+	successes        uint64
+	attemptDurations *histogram
+	waits            *histogram
+
+	// give ups are rare, so a lock is fine and lets any reason be counted
+	mu      sync.Mutex
+	giveUps map[string]uint64
+}
+
+func (s *series) snapshot() Stats {
+	stats := Stats{
+		Attempts:         atomic.LoadUint64(&s.attempts),
+		Retries:          atomic.LoadUint64(&s.retries),
+		Successes:        atomic.LoadUint64(&s.successes),
+		GiveUps:          make(map[string]uint64),
+		AttemptDurations: s.attemptDurations.snapshot(),
+		Waits:            s.waits.snapshot(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for reason, count := range s.giveUps {
+		stats.GiveUps[reason] = count
+	}
+	return stats
+}
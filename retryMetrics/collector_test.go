@@ -0,0 +1,151 @@
+package retryMetrics_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"github.com/wojnosystems/go-retry/retryMetrics"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+var _ = Describe("Collector", func() {
+	var collector *retryMetrics.Collector
+	BeforeEach(func() {
+		collector = retryMetrics.NewCollector()
+		collector.Buckets = []time.Duration{100 * time.Millisecond, 10 * time.Millisecond}
+	})
+
+	It("records what the loop does", func() {
+		strategy := retry.NewUpTo(0, 2)
+		strategy.Observer = collector.Observer("db")
+		_ = strategy.Retry(context.Background(), func() error {
+			return retryMocks.ErrRetry
+		})
+		mock := &retryMocks.Callback{Responses: []error{retryMocks.ErrRetry, retryError.StopSuccess}}
+		_ = strategy.Retry(context.Background(), mock.Generator())
+
+		stats := collector.Snapshot()["db"]
+		Expect(stats.Attempts).Should(Equal(uint64(4)))
+		Expect(stats.Retries).Should(Equal(uint64(2)))
+		Expect(stats.Successes).Should(Equal(uint64(1)))
+		Expect(stats.GiveUps).Should(Equal(map[string]uint64{"attempts_exhausted": 1}))
+		Expect(stats.AttemptDurations.Count).Should(Equal(uint64(4)))
+		Expect(stats.Waits.Count).Should(Equal(uint64(2)))
+	})
+
+	It("records strategies observed through the context", func() {
+		ctx := retryLoop.WithObserver(context.Background(), collector.Observer("ctx"))
+		_ = retry.NewUpTo(0, 3).Retry(ctx, func() error {
+			return retryError.StopSuccess
+		})
+		Expect(collector.Snapshot()["ctx"].Successes).Should(Equal(uint64(1)))
+	})
+
+	It("counts durations into cumulative, sorted buckets", func() {
+		observer := collector.Observer("api")
+		for _, wait := range []time.Duration{5 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond, time.Second} {
+			observer.OnWait(retryLoop.AttemptInfo{}, wait)
+		}
+		Expect(collector.Snapshot()["api"].Waits).Should(Equal(retryMetrics.Histogram{
+			Count: 4,
+			Sum:   1065 * time.Millisecond,
+			Buckets: []retryMetrics.Bucket{
+				{UpperBound: 10 * time.Millisecond, Count: 2},
+				{UpperBound: 100 * time.Millisecond, Count: 3},
+			},
+		}))
+	})
+
+	It("shares the metrics of observers with the same name", func() {
+		collector.Observer("db").OnAttemptStart(retryLoop.AttemptInfo{})
+		collector.Observer("db").OnAttemptStart(retryLoop.AttemptInfo{})
+		Expect(collector.Snapshot()).Should(HaveLen(1))
+		Expect(collector.Snapshot()["db"].Attempts).Should(Equal(uint64(2)))
+	})
+
+	It("is safe for concurrent use", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				observer := collector.Observer("db")
+				for j := 0; j < 1000; j++ {
+					observer.OnAttemptStart(retryLoop.AttemptInfo{})
+					observer.OnAttemptEnd(retryLoop.AttemptInfo{}, nil, time.Millisecond)
+					observer.OnGiveUp(retryLoop.AttemptInfo{}, retryLoop.GiveUpContextDone, nil)
+				}
+			}()
+		}
+		wg.Wait()
+		stats := collector.Snapshot()["db"]
+		Expect(stats.Attempts).Should(Equal(uint64(8000)))
+		Expect(stats.AttemptDurations.Buckets[0].Count).Should(Equal(uint64(8000)))
+		Expect(stats.GiveUps["context_done"]).Should(Equal(uint64(8000)))
+	})
+
+	It("writes the Prometheus text format", func() {
+		observer := collector.Observer(`say "hi"`)
+		observer.OnAttemptStart(retryLoop.AttemptInfo{})
+		observer.OnAttemptEnd(retryLoop.AttemptInfo{}, nil, 20*time.Millisecond)
+		observer.OnWait(retryLoop.AttemptInfo{}, 500*time.Millisecond)
+		observer.OnGiveUp(retryLoop.AttemptInfo{}, retryLoop.GiveUpAttemptsExhausted, nil)
+
+		var out bytes.Buffer
+		Expect(collector.WritePrometheus(&out)).Should(Succeed())
+		Expect(out.String()).Should(Equal(`# HELP retry_attempts_total Attempts started.
+# TYPE retry_attempts_total counter
+retry_attempts_total{strategy="say \"hi\""} 1
+# HELP retry_retries_total Waits before trying again.
+# TYPE retry_retries_total counter
+retry_retries_total{strategy="say \"hi\""} 1
+# HELP retry_successes_total Retries that ended with a successful attempt.
+# TYPE retry_successes_total counter
+retry_successes_total{strategy="say \"hi\""} 0
+# HELP retry_give_ups_total Retries that stopped without succeeding, by reason.
+# TYPE retry_give_ups_total counter
+retry_give_ups_total{strategy="say \"hi\"",reason="attempts_exhausted"} 1
+# HELP retry_attempt_duration_seconds How long attempts took.
+# TYPE retry_attempt_duration_seconds histogram
+retry_attempt_duration_seconds_bucket{strategy="say \"hi\"",le="0.01"} 0
+retry_attempt_duration_seconds_bucket{strategy="say \"hi\"",le="0.1"} 1
+retry_attempt_duration_seconds_bucket{strategy="say \"hi\"",le="+Inf"} 1
+retry_attempt_duration_seconds_sum{strategy="say \"hi\""} 0.02
+retry_attempt_duration_seconds_count{strategy="say \"hi\""} 1
+# HELP retry_wait_seconds How long was waited before trying again.
+# TYPE retry_wait_seconds histogram
+retry_wait_seconds_bucket{strategy="say \"hi\"",le="0.01"} 0
+retry_wait_seconds_bucket{strategy="say \"hi\"",le="0.1"} 0
+retry_wait_seconds_bucket{strategy="say \"hi\"",le="+Inf"} 1
+retry_wait_seconds_sum{strategy="say \"hi\""} 0.5
+retry_wait_seconds_count{strategy="say \"hi\""} 1
+`))
+	})
+
+	It("serves the Prometheus text format", func() {
+		collector.Observer("db").OnAttemptStart(retryLoop.AttemptInfo{})
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		Expect(recorder.Header().Get("Content-Type")).Should(HavePrefix("text/plain; version=0.0.4"))
+		Expect(recorder.Body.String()).Should(ContainSubstring(`retry_attempts_total{strategy="db"} 1`))
+	})
+
+	It("is an expvar.Var", func() {
+		observer := collector.Observer("db")
+		observer.OnAttemptStart(retryLoop.AttemptInfo{})
+		observer.OnWait(retryLoop.AttemptInfo{}, 50*time.Millisecond)
+		var decoded map[string]map[string]interface{}
+		Expect(json.Unmarshal([]byte(collector.String()), &decoded)).Should(Succeed())
+		Expect(decoded["db"]["attempts"]).Should(BeEquivalentTo(1))
+		Expect(decoded["db"]["wait_seconds"]).Should(HaveKeyWithValue("sum_seconds", 0.05))
+		Expect(decoded["db"]["wait_seconds"]).Should(HaveKeyWithValue("buckets", HaveKeyWithValue("0.1", BeEquivalentTo(1))))
+	})
+})
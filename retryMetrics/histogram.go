@@ -0,0 +1,89 @@
+package retryMetrics
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets are the histogram buckets used when a Collector's Buckets is nil
+var DefaultBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// Histogram is a snapshot of how durations were distributed
+type Histogram struct {
+	// Count is how many durations were observed
+	Count uint64
+
+	// Sum is the total of every duration observed
+	Sum time.Duration
+
+	// Buckets count the durations no longer than each upper bound, in increasing order of the bound. Like Prometheus
+	// buckets, they are cumulative, so each includes the durations counted by the buckets before it. Durations
+	// longer than the last bound are only in Count
+	Buckets []Bucket
+}
+
+// Bucket is how many durations were no longer than UpperBound
+type Bucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// histogram counts durations into buckets. It is safe for concurrent use and never blocks
+type histogram struct {
+	bounds []time.Duration
+
+	// counts has one entry per bound, counting the durations that fell in that bucket only
+	counts []uint64
+	count  uint64
+	sum    int64
+}
+
+func newHistogram(bounds []time.Duration) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	for i, bound := range h.bounds {
+		if d <= bound {
+			atomic.AddUint64(&h.counts[i], 1)
+			break
+		}
+	}
+	atomic.AddInt64(&h.sum, int64(d))
+	atomic.AddUint64(&h.count, 1)
+}
+
+func (h *histogram) snapshot() Histogram {
+	snapshot := Histogram{
+		Count:   atomic.LoadUint64(&h.count),
+		Sum:     time.Duration(atomic.LoadInt64(&h.sum)),
+		Buckets: make([]Bucket, len(h.bounds)),
+	}
+	cumulative := uint64(0)
+	for i, bound := range h.bounds {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		snapshot.Buckets[i] = Bucket{UpperBound: bound, Count: cumulative}
+	}
+	if snapshot.Count < cumulative {
+		// an observation was counted in its bucket, but not yet in count
+		snapshot.Count = cumulative
+	}
+	return snapshot
+}
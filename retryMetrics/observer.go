@@ -0,0 +1,35 @@
+package retryMetrics
+
+import (
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"sync/atomic"
+	"time"
+)
+
+// observer records what the loop does into the series of one strategy
+type observer struct {
+	series *series
+}
+
+func (o *observer) OnAttemptStart(_ retryLoop.AttemptInfo) {
+	atomic.AddUint64(&o.series.attempts, 1)
+}
+
+func (o *observer) OnAttemptEnd(_ retryLoop.AttemptInfo, _ error, duration time.Duration) {
+	o.series.attemptDurations.observe(duration)
+}
+
+func (o *observer) OnWait(_ retryLoop.AttemptInfo, wait time.Duration) {
+	atomic.AddUint64(&o.series.retries, 1)
+	o.series.waits.observe(wait)
+}
+
+func (o *observer) OnGiveUp(_ retryLoop.AttemptInfo, reason retryLoop.GiveUpReason, _ error) {
+	o.series.mu.Lock()
+	defer o.series.mu.Unlock()
+	o.series.giveUps[reason.String()]++
+}
+
+func (o *observer) OnSuccess(_ retryLoop.AttemptInfo) {
+	atomic.AddUint64(&o.series.successes, 1)
+}
@@ -0,0 +1,102 @@
+package retryMetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WritePrometheus writes every metric in the Prometheus text exposition format. Each series is labeled with the
+// strategy's name, and give ups also with the reason. Durations are in seconds
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	snapshot := c.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := bufio.NewWriter(w)
+	writeCounter(out, "retry_attempts_total", "Attempts started.", snapshot, names, func(stats Stats) uint64 {
+		return stats.Attempts
+	})
+	writeCounter(out, "retry_retries_total", "Waits before trying again.", snapshot, names, func(stats Stats) uint64 {
+		return stats.Retries
+	})
+	writeCounter(out, "retry_successes_total", "Retries that ended with a successful attempt.", snapshot, names, func(stats Stats) uint64 {
+		return stats.Successes
+	})
+
+	writeHeader(out, "retry_give_ups_total", "Retries that stopped without succeeding, by reason.", "counter")
+	for _, name := range names {
+		giveUps := snapshot[name].GiveUps
+		reasons := make([]string, 0, len(giveUps))
+		for reason := range giveUps {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			_, _ = fmt.Fprintf(out, "retry_give_ups_total{strategy=%s,reason=%s} %d\n", quote(name), quote(reason), giveUps[reason])
+		}
+	}
+
+	writeHistogram(out, "retry_attempt_duration_seconds", "How long attempts took.", snapshot, names, func(stats Stats) Histogram {
+		return stats.AttemptDurations
+	})
+	writeHistogram(out, "retry_wait_seconds", "How long was waited before trying again.", snapshot, names, func(stats Stats) Histogram {
+		return stats.Waits
+	})
+	return out.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format, so a Collector can be scraped directly
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", prometheusContentType)
+	_ = c.WritePrometheus(w)
+}
+
+func writeHeader(out io.Writer, metric, help, kind string) {
+	_, _ = fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", metric, help, metric, kind)
+}
+
+func writeCounter(out io.Writer, metric, help string, snapshot map[string]Stats, names []string, value func(Stats) uint64) {
+	writeHeader(out, metric, help, "counter")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(out, "%s{strategy=%s} %d\n", metric, quote(name), value(snapshot[name]))
+	}
+}
+
+func writeHistogram(out io.Writer, metric, help string, snapshot map[string]Stats, names []string, value func(Stats) Histogram) {
+	writeHeader(out, metric, help, "histogram")
+	for _, name := range names {
+		h := value(snapshot[name])
+		label := quote(name)
+		for _, bucket := range h.Buckets {
+			_, _ = fmt.Fprintf(out, "%s_bucket{strategy=%s,le=%q} %d\n", metric, label, formatSeconds(bucket.UpperBound), bucket.Count)
+		}
+		_, _ = fmt.Fprintf(out, "%s_bucket{strategy=%s,le=\"+Inf\"} %d\n", metric, label, h.Count)
+		_, _ = fmt.Fprintf(out, "%s_sum{strategy=%s} %s\n", metric, label, formatSeconds(h.Sum))
+		_, _ = fmt.Fprintf(out, "%s_count{strategy=%s} %d\n", metric, label, h.Count)
+	}
+}
+
+// formatSeconds writes d in seconds, as short as possible
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+// labelEscaper escapes label values as the text exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote returns value as a quoted label value
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
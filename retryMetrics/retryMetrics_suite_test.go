@@ -0,0 +1,13 @@
+package retryMetrics_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryMetrics Suite")
+}
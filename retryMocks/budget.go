@@ -0,0 +1,43 @@
+package retryMocks
+
+import (
+	"sync"
+)
+
+// Budget allows the first Retries retries, then refuses the rest. It counts how often the loop consults it.
+// It is safe for concurrent use
+type Budget struct {
+	// Retries is how many withdrawals are allowed
+	Retries uint
+
+	mu          sync.Mutex
+	requests    uint
+	withdrawals uint
+}
+
+func (b *Budget) Requested() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests++
+}
+
+func (b *Budget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.withdrawals++
+	return b.withdrawals <= b.Retries
+}
+
+// Requests is how many times Requested was called
+func (b *Budget) Requests() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.requests
+}
+
+// Withdrawals is how many times Withdraw was called, including refused withdrawals
+func (b *Budget) Withdrawals() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.withdrawals
+}
@@ -1,10 +1,16 @@
 package retryMocks
 
+import (
+	"context"
+	"github.com/wojnosystems/go-retry/retryLoop"
+)
+
 // Callback simulates successive calls to a method to be retried
 // Responses is a slice of errors to return to each invocation of retry, allowing tests to mock
 // the behavior of a thing to retry and validate it
 type Callback struct {
 	timesRun  int
+	attempts  []retryLoop.AttemptInfo
 	Responses []error
 }
 
@@ -18,7 +24,22 @@ func (c *Callback) Generator() func() error {
 	}
 }
 
+// AttemptGenerator is like Generator, but returns a retryLoop.AttemptCallbackFunc that records the information
+// about each attempt. Retrieve the recorded information with Attempts
+func (c *Callback) AttemptGenerator() retryLoop.AttemptCallbackFunc {
+	generator := c.Generator()
+	return func(_ context.Context, attempt retryLoop.AttemptInfo) error {
+		c.attempts = append(c.attempts, attempt)
+		return generator()
+	}
+}
+
 // TimesRun gets the number of times Generator's returned function was called
 func (c *Callback) TimesRun() int {
 	return c.timesRun
 }
+
+// Attempts gets the information given to each call of AttemptGenerator's returned function
+func (c *Callback) Attempts() []retryLoop.AttemptInfo {
+	return c.attempts
+}
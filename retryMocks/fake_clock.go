@@ -0,0 +1,108 @@
+package retryMocks
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a retrySleep.Clock that only moves when Advance is called. Anything waiting on the clock is released
+// once the clock has been advanced past the end of its wait. It is safe for concurrent use
+type FakeClock struct {
+	mu       sync.Mutex
+	changed  *sync.Cond
+	now      time.Time
+	sleepers []fakeSleeper
+}
+
+type fakeSleeper struct {
+	until time.Time
+	wake  chan time.Time
+}
+
+// NewFakeClock creates a clock that starts at now
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{
+		now: now,
+	}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now is the time the clock was created at plus all the time it has been advanced
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once the clock is advanced by at least duration
+func (c *FakeClock) After(duration time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wake := make(chan time.Time, 1)
+	if duration <= 0 {
+		wake <- c.now
+		return wake
+	}
+	c.sleepers = append(c.sleepers, fakeSleeper{
+		until: c.now.Add(duration),
+		wake:  wake,
+	})
+	c.changed.Broadcast()
+	return wake
+}
+
+// Advance moves the clock forward by duration and wakes everything waiting until then
+func (c *FakeClock) Advance(duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(duration)
+	pending := c.sleepers[:0]
+	for _, sleeper := range c.sleepers {
+		if sleeper.until.After(c.now) {
+			pending = append(pending, sleeper)
+		} else {
+			sleeper.wake <- c.now
+		}
+	}
+	c.sleepers = pending
+	c.changed.Broadcast()
+}
+
+// AdvanceToNextSleeper moves the clock forward to the end of the shortest pending wait and wakes it.
+// It returns how far the clock moved, which is 0 if nothing is waiting
+func (c *FakeClock) AdvanceToNextSleeper() time.Duration {
+	c.mu.Lock()
+	if len(c.sleepers) == 0 {
+		c.mu.Unlock()
+		return 0
+	}
+	next := c.sleepers[0].until
+	for _, sleeper := range c.sleepers[1:] {
+		if sleeper.until.Before(next) {
+			next = sleeper.until
+		}
+	}
+	duration := next.Sub(c.now)
+	c.mu.Unlock()
+	c.Advance(duration)
+	return duration
+}
+
+// Sleepers is the number of waits that have not finished yet.
+// A waiter abandoned because its context was done still counts until the clock is advanced past it
+func (c *FakeClock) Sleepers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sleepers)
+}
+
+// WaitForSleepers blocks until at least count waits are pending. Use this to know when the code under test is
+// waiting on the clock before calling Advance
+func (c *FakeClock) WaitForSleepers(count int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.sleepers) < count {
+		c.changed.Wait()
+	}
+}
@@ -0,0 +1,63 @@
+package retryMocks
+
+import (
+	"fmt"
+	"github.com/wojnosystems/go-retry/retryLoop"
+	"sync"
+	"time"
+)
+
+// Observer records each call made to it as a readable event, such as "start 1" or "give up attempts_exhausted",
+// so tests can check the order in which the loop notifies observers. It is safe for concurrent use
+type Observer struct {
+	mu     sync.Mutex
+	events []string
+	waits  []time.Duration
+}
+
+func (o *Observer) record(event string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *Observer) OnAttemptStart(attempt retryLoop.AttemptInfo) {
+	o.record(fmt.Sprintf("start %d", attempt.Number))
+}
+
+func (o *Observer) OnAttemptEnd(attempt retryLoop.AttemptInfo, err error, _ time.Duration) {
+	if err == nil {
+		o.record(fmt.Sprintf("end %d", attempt.Number))
+		return
+	}
+	o.record(fmt.Sprintf("end %d %s", attempt.Number, err))
+}
+
+func (o *Observer) OnWait(attempt retryLoop.AttemptInfo, wait time.Duration) {
+	o.mu.Lock()
+	o.waits = append(o.waits, wait)
+	o.mu.Unlock()
+	o.record(fmt.Sprintf("wait %d", attempt.Number))
+}
+
+func (o *Observer) OnGiveUp(attempt retryLoop.AttemptInfo, reason retryLoop.GiveUpReason, _ error) {
+	o.record(fmt.Sprintf("give up %s", reason))
+}
+
+func (o *Observer) OnSuccess(attempt retryLoop.AttemptInfo) {
+	o.record(fmt.Sprintf("success %d", attempt.Number))
+}
+
+// Events returns the events recorded so far, in order
+func (o *Observer) Events() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string{}, o.events...)
+}
+
+// Waits returns the durations given to each OnWait, in order
+func (o *Observer) Waits() []time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]time.Duration{}, o.waits...)
+}
@@ -0,0 +1,13 @@
+package retryMocks
+
+// Random is a deterministic random source for use with the retry Jitter types.
+// Fraction is where in the requested range the "random" number falls: 0 is always the lowest value,
+// 1 is always the highest value
+type Random struct {
+	Fraction float64
+}
+
+// Int63n returns a number in [0,n) at Fraction of the way through the range
+func (r *Random) Int63n(n int64) int64 {
+	return int64(r.Fraction * float64(n-1))
+}
@@ -0,0 +1,13 @@
+package retryOtel_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryOtel(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryOtel Suite")
+}
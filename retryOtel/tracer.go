@@ -0,0 +1,69 @@
+// Package retryOtel traces retries with OpenTelemetry. It is its own module, so that the retry packages do not
+// depend on OpenTelemetry unless this package is used
+package retryOtel
+
+import (
+	"context"
+	"fmt"
+	"github.com/wojnosystems/go-retry/retryTrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer adapts an OpenTelemetry trace.Tracer into a retryTrace.Tracer. Set it as the Tracer of any strategy and each
+// attempt becomes an OpenTelemetry span
+type Tracer struct {
+	// Tracer starts the spans
+	Tracer trace.Tracer
+}
+
+// NewTracer creates a Tracer that starts spans with tracer, such as otel.Tracer("github.com/you/yourapp")
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{
+		Tracer: tracer,
+	}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, retryTrace.Span) {
+	ctx, otelSpan := t.Tracer.Start(ctx, name)
+	return ctx, &span{span: otelSpan}
+}
+
+// span adapts an OpenTelemetry trace.Span into a retryTrace.Span
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttributes(attributes ...retryTrace.Attribute) {
+	converted := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		converted = append(converted, keyValue(a))
+	}
+	s.span.SetAttributes(converted...)
+}
+
+// End records err, if any, as an error event and sets the span's status to Error, then ends it
+func (s *span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// keyValue converts an Attribute, falling back to its value as a string for types OpenTelemetry can't take
+func keyValue(a retryTrace.Attribute) attribute.KeyValue {
+	switch value := a.Value.(type) {
+	case int64:
+		return attribute.Int64(a.Key, value)
+	case float64:
+		return attribute.Float64(a.Key, value)
+	case bool:
+		return attribute.Bool(a.Key, value)
+	case string:
+		return attribute.String(a.Key, value)
+	default:
+		return attribute.String(a.Key, fmt.Sprint(value))
+	}
+}
@@ -0,0 +1,54 @@
+package retryOtel_test
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retry"
+	"github.com/wojnosystems/go-retry/retryError"
+	"github.com/wojnosystems/go-retry/retryMocks"
+	"github.com/wojnosystems/go-retry/retryOtel"
+	"github.com/wojnosystems/go-retry/retryTrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		recorder *tracetest.SpanRecorder
+		provider *sdktrace.TracerProvider
+		tracer   *retryOtel.Tracer
+	)
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		tracer = retryOtel.NewTracer(provider.Tracer("test"))
+	})
+
+	It("makes each attempt a child span with its attributes", func() {
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+		mock := &retryMocks.Callback{Responses: []error{retryMocks.ErrRetry, retryError.StopSuccess}}
+		strategy := retry.NewUpTo(0, 3)
+		strategy.Tracer = tracer
+		Expect(strategy.Retry(ctx, mock.Generator())).Should(Succeed())
+		parent.End()
+
+		spans := recorder.Ended()
+		Expect(spans).Should(HaveLen(3))
+		first, second := spans[0], spans[1]
+		Expect(first.Name()).Should(Equal(retryTrace.AttemptSpanName))
+		Expect(first.Parent().SpanID()).Should(Equal(parent.SpanContext().SpanID()))
+		Expect(first.Attributes()).Should(ContainElements(
+			attribute.Int64(retryTrace.AttemptKey, 1),
+			attribute.Float64(retryTrace.BackoffKey, 0),
+			attribute.String(retryTrace.OutcomeKey, retryTrace.OutcomeRetry),
+		))
+		Expect(first.Status().Code).Should(Equal(codes.Error))
+		Expect(first.Status().Description).Should(Equal(retryMocks.ErrRetryReason.Error()))
+		Expect(first.Events()).Should(HaveLen(1))
+		Expect(second.Attributes()).Should(ContainElement(attribute.String(retryTrace.OutcomeKey, retryTrace.OutcomeSuccess)))
+		Expect(second.Status().Code).Should(Equal(codes.Unset))
+	})
+})
@@ -0,0 +1,36 @@
+package retrySleep
+
+import (
+	"time"
+)
+
+// Clock tells the time and lets callers wait for time to pass. Use retryMocks.FakeClock in tests so that waits
+// happen instantly and deterministically
+type Clock interface {
+	// Now is the current time
+	Now() time.Time
+
+	// After sends the current time on the returned channel once duration has passed
+	After(duration time.Duration) <-chan time.Time
+}
+
+// SystemClock is the real clock, using the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+// OrSystem returns clock, or SystemClock if clock is nil
+func OrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
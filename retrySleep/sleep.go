@@ -7,9 +7,14 @@ import (
 
 // WithContext will block until either the ctx expires or the duration is exceeded
 func WithContext(ctx context.Context, duration time.Duration) {
+	WithClock(ctx, SystemClock, duration)
+}
+
+// WithClock is like WithContext, but the duration is measured by clock
+func WithClock(ctx context.Context, clock Clock, duration time.Duration) {
 	select {
 	case <-ctx.Done():
-	case <-time.After(duration):
+	case <-clock.After(duration):
 	}
 	return
 }
@@ -50,4 +50,41 @@ var _ = Describe("Sleep", func() {
 			Expect(elapsed).Should(BeNumerically("~", aVeryShortTime, 1*time.Millisecond))
 		})
 	})
+	When("using a fake clock", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			clock  *retryMocks.FakeClock
+		)
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			clock = retryMocks.NewFakeClock(time.Now())
+		})
+		AfterEach(func() {
+			cancel()
+		})
+		It("waits until the clock is advanced", func() {
+			done := make(chan struct{})
+			go func() {
+				retrySleep.WithClock(ctx, clock, aVeryLongTime)
+				close(done)
+			}()
+			clock.WaitForSleepers(1)
+			clock.Advance(aVeryLongTime - 1)
+			Consistently(done).ShouldNot(BeClosed())
+			clock.Advance(1)
+			Eventually(done).Should(BeClosed())
+			Expect(clock.Sleepers()).Should(Equal(0))
+		})
+		It("stops waiting when the context is done", func() {
+			done := make(chan struct{})
+			go func() {
+				retrySleep.WithClock(ctx, clock, aVeryLongTime)
+				close(done)
+			}()
+			clock.WaitForSleepers(1)
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+	})
 })
@@ -0,0 +1,31 @@
+package retryTrace
+
+// AttemptSpanName is the name of the span made for each attempt
+const AttemptSpanName = "retry.attempt"
+
+// The attributes set on each attempt's span
+const (
+	// AttemptKey is the number of the attempt, starting at 1
+	AttemptKey = "retry.attempt"
+
+	// BackoffKey is how long was waited before the attempt, in seconds. It is 0 for the first attempt
+	BackoffKey = "retry.backoff_seconds"
+
+	// OutcomeKey is what the loop does with the attempt's result, one of the Outcome values
+	OutcomeKey = "retry.outcome"
+
+	// ClassifiedKey is set, to Retry or Stop, if the error was given to the strategy's Classifier
+	ClassifiedKey = "retry.classified"
+)
+
+// The values of OutcomeKey and ClassifiedKey
+const (
+	// OutcomeSuccess means the attempt succeeded
+	OutcomeSuccess = "success"
+
+	// OutcomeRetry means the attempt failed with a retryable error
+	OutcomeRetry = "retry"
+
+	// OutcomeStop means the attempt failed with an error that is not retryable
+	OutcomeStop = "stop"
+)
@@ -0,0 +1,108 @@
+package retryTrace
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder is a Tracer that keeps every span in memory, so tests can check what was traced.
+// It is safe for concurrent use
+type Recorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// RecordedSpan is a span started by a Recorder
+type RecordedSpan struct {
+	// ID identifies the span, starting at 1 in the order spans were started
+	ID int
+
+	// ParentID is the ID of the span this is a child of, 0 if it has no parent
+	ParentID int
+
+	// Name is the name the span was started with
+	Name string
+
+	// Attributes are the attributes set on the span, by key
+	Attributes map[string]interface{}
+
+	// Ended is true once End was called
+	Ended bool
+
+	// Err is the error given to End
+	Err error
+}
+
+// recorderKey is the context key of the span started by a Recorder
+type recorderKey struct{}
+
+func (r *Recorder) Start(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &recorderSpan{recorder: r, index: len(r.spans)}
+	recorded := RecordedSpan{
+		ID:         span.index + 1,
+		Name:       name,
+		Attributes: make(map[string]interface{}),
+	}
+	if parent, ok := ctx.Value(recorderKey{}).(*recorderSpan); ok && parent.recorder == r {
+		recorded.ParentID = parent.index + 1
+	}
+	r.spans = append(r.spans, recorded)
+	return context.WithValue(ctx, recorderKey{}, span), span
+}
+
+// Spans returns a copy of every span started, in the order they were started
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spans := make([]RecordedSpan, len(r.spans))
+	for i, span := range r.spans {
+		spans[i] = span
+		spans[i].Attributes = make(map[string]interface{}, len(span.Attributes))
+		for key, value := range span.Attributes {
+			spans[i].Attributes[key] = value
+		}
+	}
+	return spans
+}
+
+// Children returns the spans whose parent is the span with the given ID, in the order they were started
+func (r *Recorder) Children(parentID int) (children []RecordedSpan) {
+	for _, span := range r.Spans() {
+		if span.ParentID == parentID {
+			children = append(children, span)
+		}
+	}
+	return
+}
+
+// recorderSpan updates its RecordedSpan in the Recorder
+type recorderSpan struct {
+	recorder *Recorder
+	index    int
+}
+
+func (s *recorderSpan) SetAttributes(attributes ...Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, attribute := range attributes {
+		s.recorder.spans[s.index].Attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *recorderSpan) End(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	recorded := &s.recorder.spans[s.index]
+	if recorded.Ended {
+		return
+	}
+	recorded.Ended = true
+	recorded.Err = err
+}
@@ -0,0 +1,56 @@
+package retryTrace_test
+
+import (
+	"context"
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/wojnosystems/go-retry/retryTrace"
+)
+
+var _ = Describe("Recorder", func() {
+	var recorder *retryTrace.Recorder
+	BeforeEach(func() {
+		recorder = retryTrace.NewRecorder()
+	})
+
+	It("records spans and their attributes", func() {
+		_, span := recorder.Start(context.Background(), "work")
+		span.SetAttributes(retryTrace.Int64("n", 1), retryTrace.String("s", "a"))
+		span.SetAttributes(retryTrace.Int64("n", 2), retryTrace.Bool("b", true), retryTrace.Float64("f", 0.5))
+		Expect(recorder.Spans()).Should(Equal([]retryTrace.RecordedSpan{{
+			ID:         1,
+			Name:       "work",
+			Attributes: map[string]interface{}{"n": int64(2), "s": "a", "b": true, "f": 0.5},
+		}}))
+	})
+
+	It("records the error the span ended with, once", func() {
+		errFailed := errors.New("failed")
+		_, span := recorder.Start(context.Background(), "work")
+		span.End(errFailed)
+		span.End(nil)
+		spans := recorder.Spans()
+		Expect(spans[0].Ended).Should(BeTrue())
+		Expect(spans[0].Err).Should(Equal(errFailed))
+	})
+
+	It("records children of the span in the context", func() {
+		ctx, parent := recorder.Start(context.Background(), "parent")
+		_, _ = recorder.Start(ctx, "first")
+		_, _ = recorder.Start(ctx, "second")
+		_, _ = recorder.Start(context.Background(), "other")
+		parent.End(nil)
+		children := recorder.Children(1)
+		Expect(children).Should(HaveLen(2))
+		Expect(children[0].Name).Should(Equal("first"))
+		Expect(children[1].Name).Should(Equal("second"))
+		Expect(recorder.Children(0)).Should(HaveLen(2))
+	})
+
+	It("ignores spans from another Recorder", func() {
+		ctx, _ := retryTrace.NewRecorder().Start(context.Background(), "elsewhere")
+		_, _ = recorder.Start(ctx, "here")
+		Expect(recorder.Spans()[0].ParentID).Should(BeZero())
+	})
+})
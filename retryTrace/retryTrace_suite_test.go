@@ -0,0 +1,13 @@
+package retryTrace_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryTrace(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RetryTrace Suite")
+}
@@ -0,0 +1,49 @@
+package retryTrace
+
+import (
+	"context"
+)
+
+// Tracer starts spans. Set it as the Tracer of any strategy, see retryLoop.Options, and each attempt is made inside
+// its own span, a child of the span in the context given to the retry, if any.
+// Implement it to send spans to your tracing system, or use the otel adapter module to use OpenTelemetry
+type Tracer interface {
+	// Start starts a span called name, as a child of the span in ctx, if any. The returned context contains the new
+	// span, so that spans started from it are its children
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation being traced
+type Span interface {
+	// SetAttributes adds attributes to the span, replacing those with the same key
+	SetAttributes(attributes ...Attribute)
+
+	// End finishes the span. err is nil if the operation succeeded, otherwise it is recorded as the reason it failed
+	End(err error)
+}
+
+// Attribute describes a span. Value is an int64, float64, bool or string
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Int64 creates an Attribute with an int64 value
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Float64 creates an Attribute with a float64 value
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool creates an Attribute with a bool value
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// String creates an Attribute with a string value
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}